@@ -3,7 +3,11 @@ package ring
 // MD5 is used for hashing only, and not for any cryptographic/security related functionality.
 import (
 	"crypto/md5" // #nosec G501
+	"crypto/sha256"
 	"encoding/binary"
+	"hash/crc32"
+	"hash/fnv"
+	"math/bits"
 )
 
 // MD5 uses the MD5 hashing algorithm to hash an identifier into a uint64.
@@ -11,4 +15,109 @@ func MD5(identifier string) uint64 {
 	hash := md5.Sum([]byte(identifier)) // #nosec G401
 	hashSlice := hash[:]
 	return binary.BigEndian.Uint64(hashSlice)
-}
\ No newline at end of file
+}
+
+// SHA256 uses the SHA-256 hashing algorithm to hash an identifier into a uint64, taking the first 8 bytes of
+// the digest. It is slower than MD5, but its stronger avalanche spreads highly structured identifiers more evenly.
+func SHA256(identifier string) uint64 {
+	hash := sha256.Sum256([]byte(identifier))
+	return binary.BigEndian.Uint64(hash[:8])
+}
+
+// FNV1a uses the 64 bit FNV-1a hashing algorithm to hash an identifier into a uint64.
+func FNV1a(identifier string) uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(identifier))
+	return hash.Sum64()
+}
+
+// CRC32 uses the IEEE CRC-32 checksum to hash an identifier into a uint64, repeating the checksum in both
+// halves so that hashes spread across the whole ring rather than clustering within its first 1/2^32.
+// Identifiers with the same checksum still collide, so it trades distribution quality for speed.
+func CRC32(identifier string) uint64 {
+	checksum := uint64(crc32.ChecksumIEEE([]byte(identifier)))
+	return checksum<<32 | checksum
+}
+
+// Murmur3 uses the 128 bit x64 variant of the MurmurHash3 hashing algorithm with a seed of zero to hash an
+// identifier into a uint64, folding the two 64 bit halves of the hash together with XOR.
+func Murmur3(identifier string) uint64 {
+	h1, h2 := murmur3x64128([]byte(identifier))
+	return h1 ^ h2
+}
+
+const (
+	murmur3C1 = 0x87c37b91114253d5
+	murmur3C2 = 0x4cf5ad432745937f
+)
+
+// murmur3x64128 computes the two 64 bit halves of the 128 bit x64 variant of MurmurHash3 with a seed of zero.
+func murmur3x64128(data []byte) (uint64, uint64) {
+	var h1, h2 uint64
+	length := uint64(len(data))
+
+	// Mix each 16 byte block into the hash.
+	for len(data) >= 16 {
+		k1 := binary.LittleEndian.Uint64(data)
+		k2 := binary.LittleEndian.Uint64(data[8:])
+		data = data[16:]
+
+		h1 ^= murmur3MixK1(k1)
+		h1 = bits.RotateLeft64(h1, 27) + h2
+		h1 = h1*5 + 0x52dce729
+
+		h2 ^= murmur3MixK2(k2)
+		h2 = bits.RotateLeft64(h2, 31) + h1
+		h2 = h2*5 + 0x38495ab5
+	}
+
+	// Mix the remaining tail bytes into the hash.
+	var k1, k2 uint64
+	for idx := len(data) - 1; idx >= 8; idx-- {
+		k2 = k2<<8 | uint64(data[idx])
+	}
+	for idx := min(len(data), 8) - 1; idx >= 0; idx-- {
+		k1 = k1<<8 | uint64(data[idx])
+	}
+	if len(data) > 8 {
+		h2 ^= murmur3MixK2(k2)
+	}
+	if len(data) > 0 {
+		h1 ^= murmur3MixK1(k1)
+	}
+
+	h1 ^= length
+	h2 ^= length
+
+	h1 += h2
+	h2 += h1
+
+	h1 = murmur3FMix(h1)
+	h2 = murmur3FMix(h2)
+
+	h1 += h2
+	h2 += h1
+
+	return h1, h2
+}
+
+func murmur3MixK1(k1 uint64) uint64 {
+	k1 *= murmur3C1
+	k1 = bits.RotateLeft64(k1, 31)
+	return k1 * murmur3C2
+}
+
+func murmur3MixK2(k2 uint64) uint64 {
+	k2 *= murmur3C2
+	k2 = bits.RotateLeft64(k2, 33)
+	return k2 * murmur3C1
+}
+
+func murmur3FMix(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
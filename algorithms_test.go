@@ -0,0 +1,114 @@
+package ring
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// requireEvenDistribution asserts that hashing many identifiers spreads them evenly between equal ranges of the ring.
+func requireEvenDistribution(t *testing.T, hash func(string) uint64) {
+	const (
+		buckets     = 16
+		identifiers = 160000
+	)
+
+	counts := make([]int, buckets)
+	for idx := 0; idx < identifiers; idx++ {
+		counts[hash(strconv.Itoa(idx))>>60]++
+	}
+
+	expected := identifiers / buckets
+	for _, count := range counts {
+		require.InDelta(t, expected, count, float64(expected)/20)
+	}
+}
+
+func TestMD5Distribution(t *testing.T) {
+	requireEvenDistribution(t, MD5)
+}
+
+// bucketVariation measures the coefficient of variation of the number of sequential identifiers hashed into each
+// of many equal ranges of the ring.
+func bucketVariation(hash func(string) uint64) float64 {
+	const (
+		buckets     = 256
+		identifiers = 256000
+	)
+
+	counts := make([]int, buckets)
+	for idx := 0; idx < identifiers; idx++ {
+		counts[hash("node"+strconv.Itoa(idx))>>56]++
+	}
+
+	mean := float64(identifiers) / buckets
+	variance := 0.0
+	for _, count := range counts {
+		variance += (float64(count) - mean) * (float64(count) - mean)
+	}
+
+	return math.Sqrt(variance/buckets) / mean
+}
+
+func TestSHA256(t *testing.T) {
+	// The first 8 bytes of the known SHA-256 digest of the empty string.
+	require.Equal(t, uint64(0xe3b0c44298fc1c14), SHA256(""))
+}
+
+func TestSHA256Distribution(t *testing.T) {
+	requireEvenDistribution(t, SHA256)
+	require.LessOrEqual(t, bucketVariation(SHA256), bucketVariation(MD5))
+}
+
+func TestMurmur3(t *testing.T) {
+	for _, test := range []struct {
+		identifier string
+		h1, h2     uint64
+	}{
+		{identifier: "", h1: 0, h2: 0},
+		{identifier: "hello", h1: 0xcbd8a7b341bd9b02, h2: 0x5b1e906a48ae1d19},
+		{identifier: "The quick brown fox jumps over the lazy dog", h1: 0xe34bbc7bbc071b6c, h2: 0x7a433ca9c49a9347},
+	} {
+		h1, h2 := murmur3x64128([]byte(test.identifier))
+		require.Equal(t, test.h1, h1, test.identifier)
+		require.Equal(t, test.h2, h2, test.identifier)
+		require.Equal(t, test.h1^test.h2, Murmur3(test.identifier), test.identifier)
+	}
+}
+
+func TestMurmur3Distribution(t *testing.T) {
+	requireEvenDistribution(t, Murmur3)
+}
+
+func TestFNV1a(t *testing.T) {
+	// Known FNV-1a 64 bit vectors.
+	require.Equal(t, uint64(0xcbf29ce484222325), FNV1a(""))
+	require.Equal(t, uint64(0xaf63dc4c8601ec8c), FNV1a("a"))
+	require.Equal(t, FNV1a("key"), FNV1a("key"))
+}
+
+func TestCRC32(t *testing.T) {
+	require.Equal(t, uint64(0), CRC32(""))
+	require.Equal(t, uint64(0xcbf43926cbf43926), CRC32("123456789"))
+	require.Equal(t, CRC32("key"), CRC32("key"))
+
+	// The checksum is spread across the whole ring.
+	requireEvenDistribution(t, CRC32)
+}
+
+func TestAlgorithmsAsRingHash(t *testing.T) {
+	for _, hash := range []func(string) uint64{MD5, Murmur3, FNV1a, CRC32} {
+		ring, err := New(func(r *Ring[RingPayloadType]) {
+			r.Hash = hash
+		})
+		require.NoError(t, err)
+
+		err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+		require.NoError(t, err)
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+		require.NoError(t, err)
+		require.Equal(t, hash("1"), ring.hashesByKey["1"])
+	}
+}
@@ -0,0 +1,164 @@
+package ring
+
+import (
+	"sort"
+	"time"
+)
+
+// LoadImbalance measures how unevenly keys are distributed between nodes, as the number of keys owned by the
+// most loaded node divided by the mean number of keys per node. A perfectly balanced ring measures 1, as does
+// a ring without any nodes or keys.
+func (ring *Ring[T]) LoadImbalance() float64 {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	imbalance, _ := ring.loadImbalance()
+	return imbalance
+}
+
+// Distribution provides the number of keys currently owned by each node, including nodes owning no keys.
+// Keys held in the empty container are counted under the empty node identifier.
+func (ring *Ring[T]) Distribution() map[string]int {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	return ring.distribution()
+}
+
+// distribution counts the keys owned by each node, and by the empty container if it holds any keys.
+func (ring *Ring[T]) distribution() map[string]int {
+	counts := make(map[string]int, len(ring.vFactorByNode))
+	for node := range ring.vFactorByNode {
+		counts[node] = 0
+	}
+
+	for key := range ring.hashesByKey {
+		node, _ := ring.nodeForKey(key)
+		counts[node]++
+	}
+
+	return counts
+}
+
+// loadImbalance measures the load imbalance, additionally providing the number of keys owned by each node.
+func (ring *Ring[T]) loadImbalance() (float64, map[string]int) {
+	counts := ring.distribution()
+	delete(counts, "")
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	if len(counts) == 0 || total == 0 {
+		return 1, counts
+	}
+
+	most := 0
+	for _, count := range counts {
+		most = max(most, count)
+	}
+
+	return float64(most) / (float64(total) / float64(len(counts))), counts
+}
+
+// AutoBalance begins periodically adjusting the VFactors of nodes, once per interval of the ring's Clock, until the
+// LoadImbalance of the ring is at most the target. Watchers are notified of the net movement of keys caused by each
+// adjustment, as with ResizeNodes. Any auto balancing already running is stopped first.
+//
+// Since every adjustment moves keys, and the keys owned by a node depend on the positions of its slices rather than
+// only their number, an unreachable target causes VFactors to oscillate indefinitely. To damp this, each interval
+// adjusts a single node by a single VFactor: the most loaded node shrinks if it can, otherwise the least loaded node
+// grows. VFactors are kept between one and AutoBalanceMaxVFactor, and auto balancing should be stopped once the
+// target is reached if the keys of the ring are not expected to change.
+func (ring *Ring[T]) AutoBalance(target float64, interval time.Duration) {
+	ring.balanceMu.Lock()
+	defer ring.balanceMu.Unlock()
+
+	ring.stopAutoBalance()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	ticker := ring.Clock.NewTicker(interval)
+
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C():
+				ring.balance(target)
+			}
+		}
+	}()
+
+	ring.balanceStop = stop
+	ring.balanceDone = done
+}
+
+// StopAutoBalance stops the auto balancing started by AutoBalance and waits for it to exit.
+// It is a noop if no auto balancing is running.
+func (ring *Ring[T]) StopAutoBalance() {
+	ring.balanceMu.Lock()
+	defer ring.balanceMu.Unlock()
+
+	ring.stopAutoBalance()
+}
+
+func (ring *Ring[T]) stopAutoBalance() {
+	if ring.balanceStop == nil {
+		return
+	}
+
+	close(ring.balanceStop)
+	<-ring.balanceDone
+
+	ring.balanceStop = nil
+	ring.balanceDone = nil
+}
+
+// balance makes a single adjustment to the VFactor of a node if the load imbalance exceeds the target,
+// returning the number of keys which changed owners.
+func (ring *Ring[T]) balance(target float64) int {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	imbalance, counts := ring.loadImbalance()
+	if imbalance <= target {
+		return 0
+	}
+
+	// Visit nodes in a stable order so that adjustments are deterministic.
+	nodes := make([]string, 0, len(counts))
+	for node := range counts {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	most, least := nodes[0], nodes[0]
+	for _, node := range nodes[1:] {
+		if counts[node] > counts[most] {
+			most = node
+		}
+		if counts[node] < counts[least] {
+			least = node
+		}
+	}
+
+	identifier, vFactor := most, ring.vFactorByNode[most]-1
+	if vFactor < 1 {
+		identifier, vFactor = least, ring.vFactorByNode[least]+1
+		if vFactor > ring.AutoBalanceMaxVFactor {
+			return 0
+		}
+	}
+
+	moved, _ := ring.collectMoves(func() error {
+		return ring.resizeNode(identifier, vFactor)
+	})
+
+	return moved
+}
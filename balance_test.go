@@ -0,0 +1,130 @@
+package ring
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func unbalancedRing(t *testing.T, clock Clock) *Ring[RingPayloadType] {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.Clock = clock
+		r.AutoBalanceMaxVFactor = 4
+	})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 4})
+	require.NoError(t, err)
+	err = ring.CreateNode(Node{Identifier: "B", VFactor: 1})
+	require.NoError(t, err)
+
+	for idx := 0; idx < 200; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{
+			InnerKey: &InnerKey{Key: strconv.Itoa(idx)},
+		})
+		require.NoError(t, err)
+	}
+
+	return ring
+}
+
+func TestLoadImbalance(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+	require.Equal(t, 1.0, ring.LoadImbalance())
+
+	ring = unbalancedRing(t, newFakeClock())
+
+	_, counts := ring.loadImbalance()
+	most := max(counts["A"], counts["B"])
+	require.Equal(t, float64(most)/100, ring.LoadImbalance())
+	require.Greater(t, ring.LoadImbalance(), 1.0)
+}
+
+func TestDistribution(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "0"}})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"": 1}, ring.Distribution())
+
+	ring = unbalancedRing(t, newFakeClock())
+
+	distribution := ring.Distribution()
+	require.Len(t, distribution, 2)
+	require.Equal(t, 200, distribution["A"]+distribution["B"])
+
+	// The node with four times the VFactor owns proportionally more keys.
+	require.Greater(t, distribution["A"], 2*distribution["B"])
+}
+
+func TestBalance(t *testing.T) {
+	ring := unbalancedRing(t, newFakeClock())
+
+	// Nothing is adjusted once the target is met.
+	require.Zero(t, ring.balance(2))
+	require.Equal(t, 4, ring.vFactorByNode["A"])
+	require.Equal(t, 1, ring.vFactorByNode["B"])
+
+	_, counts := ring.loadImbalance()
+	require.Greater(t, counts["A"], counts["B"])
+
+	// The most loaded node shrinks by a single VFactor.
+	moved := ring.balance(1)
+	require.Positive(t, moved)
+	require.Equal(t, 3, ring.vFactorByNode["A"])
+	require.Equal(t, 1, ring.vFactorByNode["B"])
+
+	_, after := ring.loadImbalance()
+	require.Equal(t, moved, after["B"]-counts["B"])
+}
+
+func TestBalanceWithinBounds(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.AutoBalanceMaxVFactor = 1
+	})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+	err = ring.CreateNode(Node{Identifier: "B", VFactor: 1})
+	require.NoError(t, err)
+
+	for idx := 0; idx < 10; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{
+			InnerKey: &InnerKey{Key: strconv.Itoa(idx)},
+		}, "shared")
+		require.NoError(t, err)
+	}
+
+	// Neither node can shrink below one nor grow beyond the maximum.
+	require.Zero(t, ring.balance(1))
+	require.Equal(t, map[string]int{"A": 1, "B": 1}, ring.vFactorByNode)
+}
+
+func TestAutoBalance(t *testing.T) {
+	clock := newFakeClock()
+	ring := unbalancedRing(t, clock)
+
+	ring.AutoBalance(1, time.Second)
+
+	// Stopping waits for the adjustment made on the tick to finish.
+	clock.Advance(time.Second)
+	ring.StopAutoBalance()
+
+	node, err := ring.GetNode("A")
+	require.NoError(t, err)
+	require.Equal(t, 3, node.VFactor)
+
+	// No further adjustments are made once stopped.
+	clock.Advance(time.Second)
+
+	node, err = ring.GetNode("A")
+	require.NoError(t, err)
+	require.Equal(t, 3, node.VFactor)
+
+	ring.StopAutoBalance()
+}
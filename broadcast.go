@@ -0,0 +1,277 @@
+package ring
+
+import (
+	"sort"
+	"sync"
+)
+
+// BroadcastSlice is the sentinel slice to which every hash of a BroadcastRing belongs, as every node
+// receives every key.
+const BroadcastSlice uint64 = 0
+
+// BroadcastRing is a data structure implementing the same KeyNodeWatcher interface as the Ring,
+// with the primary difference that every node always receives every key, rather than a single
+// node receiving each key. Watchers are notified once per node for each change to a key.
+type BroadcastRing[T any] struct {
+	vFactorByNode map[string]int
+	contentByKey  map[string]T
+	hashesByKey   map[string]uint64
+	mu            sync.RWMutex
+
+	Hash func(string) uint64
+
+	watcher[T]
+}
+
+var _ KeyNodeWatcher[any] = (*BroadcastRing[any])(nil)
+
+// NewBroadcast creates a new broadcasting ring, applying all of the provided options.
+func NewBroadcast[T any](options ...func(*BroadcastRing[T])) (*BroadcastRing[T], error) {
+	ring := &BroadcastRing[T]{
+		vFactorByNode: make(map[string]int),
+		contentByKey:  make(map[string]T),
+		hashesByKey:   make(map[string]uint64),
+		Hash:          MD5,
+		watcher: watcher[T]{
+			watchers: make(map[string][]*opChans[T]),
+			Filter: func(o Op[T]) string {
+				return o.Node
+			},
+		},
+	}
+
+	for _, option := range options {
+		option(ring)
+	}
+
+	return ring, nil
+}
+
+// Emplace attempts to add a key to the ring, notifying every node of the new key. The optional hash key
+// is hashed in place of the key for the ring's state, although it has no effect on which nodes receive the key.
+func (ring *BroadcastRing[T]) Emplace(key *Key[T], hk ...string) error {
+	if key == nil {
+		return ErrNilKey
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	_, ok := ring.hashesByKey[key.InnerKey.Key]
+	if ok {
+		return ErrKeyAlreadyExists
+	}
+
+	ring.contentByKey[key.InnerKey.Key] = key.Value
+	ring.hashesByKey[key.InnerKey.Key] = ring.Hash(hashKeyOf(key, hk))
+
+	for _, node := range ring.nodes() {
+		ring.notify(Op[T]{
+			Key:     key.InnerKey.Key,
+			Node:    node,
+			Payload: key.Value,
+		})
+	}
+
+	return nil
+}
+
+// Update attempts to update the payload of the key, notifying every node of the update.
+func (ring *BroadcastRing[T]) Update(key *Key[T]) error {
+	if key == nil {
+		return ErrNilKey
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	_, ok := ring.hashesByKey[key.InnerKey.Key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	ring.contentByKey[key.InnerKey.Key] = key.Value
+
+	for _, node := range ring.nodes() {
+		ring.notify(Op[T]{
+			Key:     key.InnerKey.Key,
+			Node:    node,
+			Payload: key.Value,
+			Updated: true,
+		})
+	}
+
+	return nil
+}
+
+// Remove will remove a key from the ring, notifying every node of the removal.
+// It is a noop if the key does not exist.
+func (ring *BroadcastRing[T]) Remove(key string) {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	_, ok := ring.hashesByKey[key]
+	if !ok {
+		return
+	}
+
+	payload := ring.contentByKey[key]
+	delete(ring.contentByKey, key)
+	delete(ring.hashesByKey, key)
+
+	for _, node := range ring.nodes() {
+		ring.notify(Op[T]{
+			Key:     key,
+			Node:    node,
+			Payload: payload,
+			Removed: true,
+		})
+	}
+}
+
+// CreateNode attempts to add a new node to the ring, notifying the new node of every key in the ring.
+// The VFactor of the node is recorded, but has no effect on which keys the node receives.
+func (ring *BroadcastRing[T]) CreateNode(node Node) error {
+	if node.VFactor < 1 {
+		return ErrInvalidVFactor
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	_, ok := ring.vFactorByNode[node.Identifier]
+	if ok {
+		return ErrNodeAlreadyExists
+	}
+
+	ring.vFactorByNode[node.Identifier] = node.VFactor
+
+	for _, key := range ring.keys() {
+		ring.notify(Op[T]{
+			Key:        key,
+			Node:       node.Identifier,
+			Payload:    ring.contentByKey[key],
+			RingChange: true,
+		})
+	}
+
+	return nil
+}
+
+// DeleteNode attempts to remove a node from the ring, notifying the node of the removal of every key in the ring.
+// It is a noop if no node with the given identifier exists.
+func (ring *BroadcastRing[T]) DeleteNode(identifier string) {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	_, ok := ring.vFactorByNode[identifier]
+	if !ok {
+		return
+	}
+
+	delete(ring.vFactorByNode, identifier)
+
+	for _, key := range ring.keys() {
+		ring.notify(Op[T]{
+			Key:        key,
+			Node:       identifier,
+			Payload:    ring.contentByKey[key],
+			Removed:    true,
+			RingChange: true,
+		})
+	}
+}
+
+// UpdateNode attempts to update the VFactor of a node. Since every node receives every key regardless
+// of its VFactor, no keys move.
+func (ring *BroadcastRing[T]) UpdateNode(node Node) error {
+	if node.VFactor < 1 {
+		return ErrInvalidVFactor
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	_, ok := ring.vFactorByNode[node.Identifier]
+	if !ok {
+		return ErrNodeNotFound
+	}
+
+	ring.vFactorByNode[node.Identifier] = node.VFactor
+
+	return nil
+}
+
+// GetNode attempts to find the node with the provided identifier.
+func (ring *BroadcastRing[T]) GetNode(identifier string) (Node, error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	vFactor, ok := ring.vFactorByNode[identifier]
+	if !ok {
+		return Node{}, ErrNodeNotFound
+	}
+
+	return Node{
+		Identifier: identifier,
+		VFactor:    vFactor,
+	}, nil
+}
+
+// ListNodes lists the identifiers of the current nodes of the ring.
+func (ring *BroadcastRing[T]) ListNodes() []string {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	return ring.nodes()
+}
+
+// State provides a copy of the ring's state. Since every node receives every key, every hash belongs to
+// BroadcastSlice, which is attributed to the first node in sorted order so that the state can be loaded with
+// LoadState. While the ring has no nodes, no hash belongs to any slice, as in a Ring with no nodes.
+func (ring *BroadcastRing[T]) State() *State {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	state := &State{
+		NodesBySlice: make(map[uint64]string),
+		SlicesByHash: make(map[uint64]uint64, len(ring.hashesByKey)),
+		HashesByKey:  make(map[string]uint64, len(ring.hashesByKey)),
+	}
+
+	nodes := ring.nodes()
+	if len(nodes) > 0 {
+		state.NodesBySlice[BroadcastSlice] = nodes[0]
+	}
+
+	for key, hash := range ring.hashesByKey {
+		state.HashesByKey[key] = hash
+		if len(nodes) > 0 {
+			state.SlicesByHash[hash] = BroadcastSlice
+		}
+	}
+
+	return state
+}
+
+// nodes lists the identifiers of the current nodes in sorted order, which is the order in which they are notified.
+func (ring *BroadcastRing[T]) nodes() []string {
+	nodes := make([]string, 0, len(ring.vFactorByNode))
+	for node := range ring.vFactorByNode {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	return nodes
+}
+
+// keys lists the current keys in sorted order, which is the order in which they are notified to a node.
+func (ring *BroadcastRing[T]) keys() []string {
+	keys := make([]string, 0, len(ring.contentByKey))
+	for key := range ring.contentByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
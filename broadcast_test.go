@@ -0,0 +1,201 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastKeyAdditionAndRemovalNotification(t *testing.T) {
+	ring, err := NewBroadcast[RingPayloadType]()
+	require.NoError(t, err)
+
+	for _, identifier := range []string{"A", "B"} {
+		err = ring.CreateNode(Node{
+			Identifier: identifier,
+			VFactor:    1,
+		})
+		require.NoError(t, err)
+	}
+
+	a := ring.RegisterWatcher(Op[RingPayloadType]{
+		Node: "A",
+	})
+	b := ring.RegisterWatcher(Op[RingPayloadType]{
+		Node: "B",
+	})
+
+	go func() {
+		err := ring.Emplace(&Key[RingPayloadType]{
+			InnerKey: &InnerKey{
+				Key:   "1",
+				Order: 0,
+			},
+		})
+		require.NoError(t, err)
+	}()
+
+	require.Equal(t, Op[RingPayloadType]{
+		Key:  "1",
+		Node: "A",
+	}, <-a)
+	require.Equal(t, Op[RingPayloadType]{
+		Key:  "1",
+		Node: "B",
+	}, <-b)
+
+	go ring.Remove("1")
+
+	require.Equal(t, Op[RingPayloadType]{
+		Key:     "1",
+		Node:    "A",
+		Removed: true,
+	}, <-a)
+	require.Equal(t, Op[RingPayloadType]{
+		Key:     "1",
+		Node:    "B",
+		Removed: true,
+	}, <-b)
+}
+
+func TestBroadcastNodeCreationAndDeletionNotification(t *testing.T) {
+	ring, err := NewBroadcast[int]()
+	require.NoError(t, err)
+
+	for idx, key := range []string{"2", "1"} {
+		err = ring.Emplace(&Key[int]{
+			InnerKey: &InnerKey{Key: key},
+			Value:    idx,
+		})
+		require.NoError(t, err)
+	}
+
+	c := ring.RegisterWatcher(Op[int]{
+		Node: "A",
+	})
+
+	go func() {
+		err := ring.CreateNode(Node{
+			Identifier: "A",
+			VFactor:    1,
+		})
+		require.NoError(t, err)
+	}()
+
+	require.Equal(t, Op[int]{Key: "1", Node: "A", Payload: 1, RingChange: true}, <-c)
+	require.Equal(t, Op[int]{Key: "2", Node: "A", Payload: 0, RingChange: true}, <-c)
+
+	go func() {
+		err := ring.Update(&Key[int]{
+			InnerKey: &InnerKey{Key: "1"},
+			Value:    5,
+		})
+		require.NoError(t, err)
+	}()
+
+	require.Equal(t, Op[int]{Key: "1", Node: "A", Payload: 5, Updated: true}, <-c)
+
+	go ring.DeleteNode("A")
+
+	require.Equal(t, Op[int]{Key: "1", Node: "A", Payload: 5, Removed: true, RingChange: true}, <-c)
+	require.Equal(t, Op[int]{Key: "2", Node: "A", Payload: 0, Removed: true, RingChange: true}, <-c)
+}
+
+func TestBroadcastNodes(t *testing.T) {
+	ring, err := NewBroadcast[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "B", VFactor: 1})
+	require.NoError(t, err)
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 2})
+	require.NoError(t, err)
+
+	require.ErrorIs(t, ring.CreateNode(Node{Identifier: "A", VFactor: 1}), ErrNodeAlreadyExists)
+	require.ErrorIs(t, ring.CreateNode(Node{Identifier: "C"}), ErrInvalidVFactor)
+	require.Equal(t, []string{"A", "B"}, ring.ListNodes())
+
+	err = ring.UpdateNode(Node{Identifier: "A", VFactor: 3})
+	require.NoError(t, err)
+	require.ErrorIs(t, ring.UpdateNode(Node{Identifier: "C", VFactor: 1}), ErrNodeNotFound)
+
+	node, err := ring.GetNode("A")
+	require.NoError(t, err)
+	require.Equal(t, Node{Identifier: "A", VFactor: 3}, node)
+
+	ring.DeleteNode("A")
+	ring.DeleteNode("A")
+
+	_, err = ring.GetNode("A")
+	require.ErrorIs(t, err, ErrNodeNotFound)
+}
+
+func TestBroadcastState(t *testing.T) {
+	ring, err := NewBroadcast[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "2"}}, "shared")
+	require.NoError(t, err)
+
+	require.ErrorIs(t, ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}}), ErrKeyAlreadyExists)
+	require.ErrorIs(t, ring.Emplace(nil), ErrNilKey)
+	require.ErrorIs(t, ring.Update(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "3"}}), ErrKeyNotFound)
+
+	// No hash belongs to a slice while there are no nodes.
+	require.Equal(t, &State{
+		NodesBySlice: map[uint64]string{},
+		SlicesByHash: map[uint64]uint64{},
+		HashesByKey: map[string]uint64{
+			"1": MD5("1"),
+			"2": MD5("shared"),
+		},
+	}, ring.State())
+
+	for _, node := range []string{"B", "A"} {
+		err = ring.CreateNode(Node{Identifier: node, VFactor: 1})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, &State{
+		NodesBySlice: map[uint64]string{BroadcastSlice: "A"},
+		SlicesByHash: map[uint64]uint64{
+			MD5("1"):      BroadcastSlice,
+			MD5("shared"): BroadcastSlice,
+		},
+		HashesByKey: map[string]uint64{
+			"1": MD5("1"),
+			"2": MD5("shared"),
+		},
+	}, ring.State())
+}
+
+func TestBroadcastStateLoadState(t *testing.T) {
+	broadcast, err := NewBroadcast[RingPayloadType]()
+	require.NoError(t, err)
+
+	for _, key := range []string{"1", "2"} {
+		err = broadcast.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: key}})
+		require.NoError(t, err)
+	}
+
+	// The state loads whether or not the broadcasting ring has nodes.
+	for _, node := range []string{"", "A"} {
+		if node != "" {
+			err = broadcast.CreateNode(Node{Identifier: node, VFactor: 1})
+			require.NoError(t, err)
+		}
+
+		ring, err := New[RingPayloadType]()
+		require.NoError(t, err)
+
+		state := broadcast.State()
+		err = ring.LoadState(state)
+		require.NoError(t, err)
+		require.Equal(t, state, ring.State())
+
+		owner, err := ring.GetNodeForKey("1")
+		require.NoError(t, err)
+		require.Equal(t, node, owner)
+	}
+}
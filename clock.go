@@ -0,0 +1,36 @@
+package ring
+
+import "time"
+
+// Clock is an interface whose implementation provides the current time and tickers to the ring.
+// It allows every time dependent feature of the ring to be driven deterministically in tests.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker is an interface whose implementation delivers ticks on a channel until stopped.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+type systemClock struct{}
+
+// Now returns the current wall clock time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTicker returns a ticker backed by time.Ticker.
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (ticker systemTicker) C() <-chan time.Time {
+	return ticker.Ticker.C
+}
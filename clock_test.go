@@ -0,0 +1,94 @@
+package ring
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock. Advancing the clock delivers each elapsed tick
+// synchronously, so that the ticking goroutine has received the tick when Advance returns.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+type fakeTicker struct {
+	c       chan time.Time
+	stopped chan struct{}
+	once    sync.Once
+	period  time.Duration
+	next    time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0)}
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.now
+}
+
+func (clock *fakeClock) NewTicker(d time.Duration) Ticker {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	ticker := &fakeTicker{
+		c:       make(chan time.Time),
+		stopped: make(chan struct{}),
+		period:  d,
+		next:    clock.now.Add(d),
+	}
+	clock.tickers = append(clock.tickers, ticker)
+	return ticker
+}
+
+func (clock *fakeClock) Advance(d time.Duration) {
+	type tick struct {
+		ticker *fakeTicker
+		at     time.Time
+	}
+
+	// Collect every elapsed tick under the lock, then deliver them without it.
+	clock.mu.Lock()
+	clock.now = clock.now.Add(d)
+	var ticks []tick
+	for _, ticker := range clock.tickers {
+		for !ticker.next.After(clock.now) {
+			ticks = append(ticks, tick{ticker: ticker, at: ticker.next})
+			ticker.next = ticker.next.Add(ticker.period)
+		}
+	}
+	clock.mu.Unlock()
+
+	for _, tick := range ticks {
+		select {
+		case tick.ticker.c <- tick.at:
+		case <-tick.ticker.stopped:
+		}
+	}
+}
+
+func (ticker *fakeTicker) C() <-chan time.Time {
+	return ticker.c
+}
+
+func (ticker *fakeTicker) Stop() {
+	ticker.once.Do(func() {
+		close(ticker.stopped)
+	})
+}
+
+func TestSystemClock(t *testing.T) {
+	clock := systemClock{}
+
+	ticker := clock.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+
+	before := clock.Now()
+	require.False(t, (<-ticker.C()).Before(before))
+}
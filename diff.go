@@ -0,0 +1,68 @@
+package ring
+
+import "sort"
+
+// DiffStates computes the ops which would take watchers from one state of a ring to another, without
+// either state belonging to a live ring, so that a planned change can be previewed. Each key owned by different
+// nodes in the two states is removed from its previous node and added to its next node as a ring change, keys
+// only in the first state are removed, and keys only in the second state are added. Keys held in the empty container are
+// owned by no node, so are neither removed from nor added to any node when they move. States do not include
+// payloads, so every op has an empty payload. Ops are ordered by key.
+func DiffStates[T any](from, to *State) []Op[T] {
+	keys := make([]string, 0, len(to.HashesByKey))
+	for key := range from.HashesByKey {
+		keys = append(keys, key)
+	}
+	for key := range to.HashesByKey {
+		_, ok := from.HashesByKey[key]
+		if !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	ops := []Op[T]{}
+	for _, key := range keys {
+		prevNode, prevOk := stateNodeForKey(from, key)
+		nextNode, nextOk := stateNodeForKey(to, key)
+		if prevOk && nextOk && prevNode == nextNode {
+			continue
+		}
+
+		// Keys present in both states are moved by a ring change.
+		ringChange := prevOk && nextOk
+		if prevOk && prevNode != "" {
+			ops = append(ops, Op[T]{
+				Key:        key,
+				Node:       prevNode,
+				Removed:    true,
+				RingChange: ringChange,
+			})
+		}
+		if nextOk && nextNode != "" {
+			ops = append(ops, Op[T]{
+				Key:        key,
+				Node:       nextNode,
+				RingChange: ringChange,
+			})
+		}
+	}
+
+	return ops
+}
+
+// stateNodeForKey resolves the node owning the key in the state, which is empty for keys held in the empty container.
+// It reports false if the key is not in the state.
+func stateNodeForKey(state *State, key string) (string, bool) {
+	hash, ok := state.HashesByKey[key]
+	if !ok {
+		return "", false
+	}
+
+	slice, ok := state.SlicesByHash[hash]
+	if !ok {
+		return "", true
+	}
+
+	return state.NodesBySlice[slice], true
+}
@@ -0,0 +1,60 @@
+package ring
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffStates(t *testing.T) {
+	ring, err := NewWithNodes[RingPayloadType]([]Node{{Identifier: "A", VFactor: 2}, {Identifier: "B", VFactor: 2}})
+	require.NoError(t, err)
+
+	for idx := 0; idx < 50; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+		require.NoError(t, err)
+	}
+
+	old := ring.State()
+	require.Empty(t, DiffStates[RingPayloadType](old, old))
+
+	// Watch the live change which the diff previews.
+	ring.WatcherBuffer = 100
+	c := ring.WatchAll()
+
+	err = ring.CreateNode(Node{Identifier: "C", VFactor: 2})
+	require.NoError(t, err)
+
+	var expected []Op[RingPayloadType]
+	for len(c) > 0 {
+		expected = append(expected, <-c)
+	}
+	require.NotEmpty(t, expected)
+
+	// The ring notifies moves by hash rather than by key.
+	ops := DiffStates[RingPayloadType](old, ring.State())
+	require.ElementsMatch(t, expected, ops)
+	for _, op := range ops {
+		require.True(t, op.RingChange)
+		if op.Removed {
+			require.NotEqual(t, "C", op.Node)
+		} else {
+			require.Equal(t, "C", op.Node)
+		}
+	}
+
+	// Keys only in one of the states are removed or added.
+	next := ring.State()
+	delete(next.HashesByKey, "0")
+	next.HashesByKey["new"] = old.HashesByKey["1"]
+	node, err := ring.GetNodeForKey("0")
+	require.NoError(t, err)
+	newNode, err := ring.GetNodeForKey("1")
+	require.NoError(t, err)
+
+	require.Equal(t, []Op[RingPayloadType]{
+		{Key: "0", Node: node, Removed: true},
+		{Key: "new", Node: newNode},
+	}, DiffStates[RingPayloadType](ring.State(), next))
+}
@@ -32,4 +32,40 @@ var (
 	ErrSliceAlreadyExists = errors.New(
 		"slice with this identifier already exists",
 	)
+	ErrWatcherNotFound = errors.New(
+		"no watcher is registered for this filter",
+	)
+	ErrWatcherAlreadyExists = errors.New(
+		"a watcher is already registered for this filter",
+	)
+	ErrInconsistentState = errors.New(
+		"state is internally inconsistent",
+	)
+	ErrInvalidSnapshot = errors.New(
+		"snapshot is not in a recognized format",
+	)
+	ErrTopologyAlreadyExists = errors.New(
+		"ring already has nodes",
+	)
+	ErrCASMismatch = errors.New(
+		"payload does not match the expected payload",
+	)
+	ErrUnexpectedOwner = errors.New(
+		"key is not owned by the expected node",
+	)
+	ErrInvalidVFactor = errors.New(
+		"vFactor for a node cannot be less than one",
+	)
+	ErrBatchLengthMismatch = errors.New(
+		"batch hash keys do not correspond to the batch keys",
+	)
+	ErrInvalidTTL = errors.New(
+		"ttl must be positive",
+	)
+	ErrKeyHashCollision = errors.New(
+		"key hash collides with the hash of a slice",
+	)
+	ErrMovementExceeded = errors.New(
+		"change would move more than the maximum fraction of keys",
+	)
 )
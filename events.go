@@ -0,0 +1,115 @@
+package ring
+
+import "sync"
+
+// Event is a sealed interface implemented only by KeyOp, NodeEvent, and SliceEvent, which together describe
+// every change to a ring in the order it occurred.
+type Event[T any] interface {
+	event()
+}
+
+// KeyOp is an event carrying an op describing a change to a key, exactly as it is delivered to watchers.
+type KeyOp[T any] struct {
+	Op[T]
+}
+
+// NodeEvent is an event describing a node being created, updated, or deleted. It always precedes the
+// slice events and key ops caused by the change to the node.
+type NodeEvent struct {
+	Node    Node
+	Removed bool
+	Updated bool
+}
+
+// SliceEvent is an event describing a slice being inserted into or removed from the ring.
+// It always precedes the key ops caused by the change to the slice.
+type SliceEvent struct {
+	Slice   uint64
+	Node    string
+	Removed bool
+}
+
+func (KeyOp[T]) event()   {}
+func (NodeEvent) event()  {}
+func (SliceEvent) event() {}
+
+type eventStream[T any] struct {
+	msg  chan Event[T]
+	done chan struct{}
+	wg   *sync.WaitGroup
+}
+
+// RegisterEventStream provides a channel of every event occurring on the ring, unfiltered and in order.
+// As with watchers, events are delivered while the ring is locked, so the channel must be read promptly.
+func (ring *Ring[T]) RegisterEventStream() chan Event[T] {
+	ring.streamMu.Lock()
+	defer ring.streamMu.Unlock()
+
+	stream := &eventStream[T]{
+		msg:  make(chan Event[T]),
+		done: make(chan struct{}),
+		wg:   new(sync.WaitGroup),
+	}
+	ring.streams = append(ring.streams, stream)
+
+	return stream.msg
+}
+
+// DeregisterEventStream attempts to close the channel and delete the registration from memory.
+// It is a noop if the channel is not a registered event stream.
+func (ring *Ring[T]) DeregisterEventStream(c chan Event[T]) {
+	ring.streamMu.Lock()
+
+	var stream *eventStream[T]
+	for idx, registered := range ring.streams {
+		if registered.msg == c {
+			stream = registered
+			ring.streams, _ = removeIndex(ring.streams, idx)
+			break
+		}
+	}
+	ring.streamMu.Unlock()
+
+	if stream == nil {
+		return
+	}
+
+	close(stream.done)
+	stream.wg.Wait()
+	close(stream.msg)
+}
+
+// notify delivers the op to its matching watcher and to every event stream.
+func (ring *Ring[T]) notify(op Op[T]) {
+	ring.watcher.notify(op)
+	ring.emit(KeyOp[T]{Op: op})
+}
+
+// emitNodeEvent emits the node event to every event stream, recording the change to the node in the ring's Metrics.
+func (ring *Ring[T]) emitNodeEvent(event NodeEvent) {
+	ring.Metrics.IncNodeChange(event.Node.Identifier)
+	ring.emit(event)
+}
+
+func (ring *Ring[T]) emit(event Event[T]) {
+	ring.streamMu.Lock()
+	if len(ring.streams) == 0 {
+		ring.streamMu.Unlock()
+		return
+	}
+
+	streams := make([]*eventStream[T], len(ring.streams))
+	copy(streams, ring.streams)
+	for _, stream := range streams {
+		stream.wg.Add(1)
+	}
+	ring.streamMu.Unlock()
+
+	for _, stream := range streams {
+		select {
+		case stream.msg <- event:
+		case <-stream.done:
+		}
+		stream.wg.Done()
+	}
+}
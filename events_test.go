@@ -0,0 +1,80 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStreamOrdering(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 1
+	})
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{
+		InnerKey: &InnerKey{
+			Key: "1",
+		},
+	})
+	require.NoError(t, err)
+
+	c := ring.RegisterEventStream()
+
+	go func() {
+		err := ring.CreateNode(Node{
+			Identifier: "A",
+			VFactor:    1,
+		})
+		require.NoError(t, err)
+	}()
+
+	require.Equal(t, NodeEvent{
+		Node: Node{
+			Identifier: "A",
+			VFactor:    1,
+		},
+	}, <-c)
+	require.Equal(t, SliceEvent{
+		Slice: ring.HashSlice("A", 0),
+		Node:  "A",
+	}, <-c)
+	require.Equal(t, KeyOp[RingPayloadType]{
+		Op: Op[RingPayloadType]{
+			Key:        "1",
+			Node:       "A",
+			RingChange: true,
+		},
+	}, <-c)
+
+	go ring.DeleteNode("A")
+
+	require.Equal(t, NodeEvent{
+		Node: Node{
+			Identifier: "A",
+			VFactor:    1,
+		},
+		Removed: true,
+	}, <-c)
+	require.Equal(t, SliceEvent{
+		Slice:   ring.HashSlice("A", 0),
+		Node:    "A",
+		Removed: true,
+	}, <-c)
+	require.Equal(t, KeyOp[RingPayloadType]{
+		Op: Op[RingPayloadType]{
+			Key:        "1",
+			Node:       "A",
+			Removed:    true,
+			RingChange: true,
+		},
+	}, <-c)
+
+	ring.DeregisterEventStream(c)
+
+	_, ok := <-c
+	require.False(t, ok)
+
+	// Changes no longer block on the deregistered stream.
+	ring.Remove("1")
+}
@@ -0,0 +1,56 @@
+package ring
+
+import "time"
+
+// StartHeartbeat begins sending an op with Tick set to every registered watcher, regardless of filter,
+// once per interval of the ring's Clock. Consumers can use these ticks to distinguish an idle ring from
+// a stalled subscription, and should otherwise ignore any op with Tick set.
+// Any heartbeat already running is stopped first.
+func (ring *Ring[T]) StartHeartbeat(interval time.Duration) {
+	ring.heartbeatMu.Lock()
+	defer ring.heartbeatMu.Unlock()
+
+	ring.stopHeartbeat()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	ticker := ring.Clock.NewTicker(interval)
+
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C():
+				ring.broadcast(Op[T]{Tick: true}, stop)
+			}
+		}
+	}()
+
+	ring.heartbeatStop = stop
+	ring.heartbeatDone = done
+}
+
+// StopHeartbeat stops the heartbeat started by StartHeartbeat and waits for it to exit.
+// It is a noop if no heartbeat is running.
+func (ring *Ring[T]) StopHeartbeat() {
+	ring.heartbeatMu.Lock()
+	defer ring.heartbeatMu.Unlock()
+
+	ring.stopHeartbeat()
+}
+
+func (ring *Ring[T]) stopHeartbeat() {
+	if ring.heartbeatStop == nil {
+		return
+	}
+
+	close(ring.heartbeatStop)
+	<-ring.heartbeatDone
+
+	ring.heartbeatStop = nil
+	ring.heartbeatDone = nil
+}
@@ -0,0 +1,73 @@
+package ring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeat(t *testing.T) {
+	clock := newFakeClock()
+
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.Clock = clock
+	})
+	require.NoError(t, err)
+
+	a := ring.RegisterWatcher(Op[RingPayloadType]{
+		Node: "A",
+	})
+	b := ring.RegisterWatcher(Op[RingPayloadType]{
+		Node: "B",
+	})
+
+	watcher := a
+
+	ring.StartHeartbeat(time.Second)
+
+	go clock.Advance(time.Second)
+
+	// Watchers are not ticked in any particular order.
+	for i := 0; i < 2; i++ {
+		select {
+		case op := <-a:
+			require.Equal(t, Op[RingPayloadType]{Tick: true}, op)
+			a = nil
+		case op := <-b:
+			require.Equal(t, Op[RingPayloadType]{Tick: true}, op)
+			b = nil
+		}
+	}
+
+	ring.StopHeartbeat()
+
+	// Test noop behavior.
+	ring.StopHeartbeat()
+
+	clock.Advance(time.Second)
+
+	select {
+	case op := <-watcher:
+		t.Fatalf("unexpected op after heartbeat stopped: %v", op)
+	default:
+	}
+}
+
+func TestHeartbeatStopsWhileBlocked(t *testing.T) {
+	clock := newFakeClock()
+
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.Clock = clock
+	})
+	require.NoError(t, err)
+
+	// This watcher is never read from.
+	ring.RegisterWatcher(Op[RingPayloadType]{
+		Node: "A",
+	})
+
+	ring.StartHeartbeat(time.Second)
+	clock.Advance(time.Second)
+	ring.StopHeartbeat()
+}
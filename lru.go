@@ -0,0 +1,58 @@
+package ring
+
+import "container/list"
+
+// lru is a fixed capacity cache which evicts the least recently used entry when full.
+// It is not safe for concurrent use.
+type lru[K comparable, V any] struct {
+	capacity int
+	order    *list.List
+	entries  map[K]*list.Element
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func newLRU[K comparable, V any](capacity int) *lru[K, V] {
+	return &lru[K, V]{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[K]*list.Element),
+	}
+}
+
+// put inserts or replaces the value for the key, marking it as most recently used.
+func (cache *lru[K, V]) put(key K, value V) {
+	element, ok := cache.entries[key]
+	if ok {
+		element.Value.(*lruEntry[K, V]).value = value
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	cache.entries[key] = cache.order.PushFront(&lruEntry[K, V]{
+		key:   key,
+		value: value,
+	})
+
+	// Evict the least recently used entry once over capacity.
+	if cache.order.Len() > cache.capacity {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
+
+// get returns the value for the key, marking it as most recently used.
+func (cache *lru[K, V]) get(key K) (V, bool) {
+	element, ok := cache.entries[key]
+	if !ok {
+		var value V
+		return value, false
+	}
+
+	cache.order.MoveToFront(element)
+	return element.Value.(*lruEntry[K, V]).value, true
+}
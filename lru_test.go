@@ -0,0 +1,34 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUEviction(t *testing.T) {
+	cache := newLRU[string, int](2)
+
+	cache.put("a", 1)
+	cache.put("b", 2)
+
+	// Touch a so that b becomes the least recently used.
+	value, ok := cache.get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, value)
+
+	cache.put("c", 3)
+
+	_, ok = cache.get("b")
+	require.False(t, ok)
+
+	cache.put("a", 4)
+
+	value, ok = cache.get("a")
+	require.True(t, ok)
+	require.Equal(t, 4, value)
+
+	value, ok = cache.get("c")
+	require.True(t, ok)
+	require.Equal(t, 3, value)
+}
@@ -0,0 +1,25 @@
+package ring
+
+// Metrics is an interface whose implementation records counters of the ring's activity, such as for export to
+// a monitoring system. Every method is called while the ring is locked, so implementations must not block or
+// call back into the ring.
+type Metrics interface {
+	// IncEmplace is called once for each key emplaced.
+	IncEmplace()
+
+	// IncRemove is called once for each key removed.
+	IncRemove()
+
+	// IncNodeChange is called once for each node created, updated, or deleted.
+	IncNodeChange(node string)
+
+	// IncMovement is called once for each key moved onto the node by a change to the topology of the ring.
+	IncMovement(node string)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) IncEmplace()          {}
+func (noopMetrics) IncRemove()           {}
+func (noopMetrics) IncNodeChange(string) {}
+func (noopMetrics) IncMovement(string)   {}
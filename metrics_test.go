@@ -0,0 +1,111 @@
+package ring
+
+import (
+	"expvar"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMetrics records every counter incremented by the ring.
+type fakeMetrics struct {
+	emplaces    int
+	removes     int
+	nodeChanges map[string]int
+	movements   map[string]int
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{
+		nodeChanges: make(map[string]int),
+		movements:   make(map[string]int),
+	}
+}
+
+func (m *fakeMetrics) IncEmplace() {
+	m.emplaces++
+}
+
+func (m *fakeMetrics) IncRemove() {
+	m.removes++
+}
+
+func (m *fakeMetrics) IncNodeChange(node string) {
+	m.nodeChanges[node]++
+}
+
+func (m *fakeMetrics) IncMovement(node string) {
+	m.movements[node]++
+}
+
+// expvarMetrics adapts the counters to expvar, as an adapter to any other monitoring system would.
+type expvarMetrics struct {
+	emplaces    *expvar.Int
+	removes     *expvar.Int
+	nodeChanges *expvar.Map
+	movements   *expvar.Map
+}
+
+func (m expvarMetrics) IncEmplace()               { m.emplaces.Add(1) }
+func (m expvarMetrics) IncRemove()                { m.removes.Add(1) }
+func (m expvarMetrics) IncNodeChange(node string) { m.nodeChanges.Add(node, 1) }
+func (m expvarMetrics) IncMovement(node string)   { m.movements.Add(node, 1) }
+
+func TestMetrics(t *testing.T) {
+	metrics := newFakeMetrics()
+	ring, err := New(WithMetrics[RingPayloadType](metrics))
+	require.NoError(t, err)
+
+	// Keys moving out of the empty container are moved onto the first node.
+	for idx := 0; idx < 10; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+		require.NoError(t, err)
+	}
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "B", VFactor: 2})
+	require.NoError(t, err)
+	grown := ring.Distribution()["B"]
+	require.Positive(t, grown)
+
+	err = ring.UpdateNode(Node{Identifier: "B", VFactor: 1})
+	require.NoError(t, err)
+	shrunk := ring.Distribution()["B"]
+
+	ring.Remove("0")
+	ring.Remove("0")
+	deleted := ring.Distribution()["B"]
+	ring.DeleteNode("B")
+
+	require.Equal(t, 10, metrics.emplaces)
+	require.Equal(t, 1, metrics.removes)
+	require.Equal(t, map[string]int{"A": 1, "B": 3}, metrics.nodeChanges)
+	require.Equal(t, map[string]int{
+		"A": 10 + grown - shrunk + deleted,
+		"B": grown,
+	}, metrics.movements)
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	metrics := expvarMetrics{
+		emplaces:    new(expvar.Int),
+		removes:     new(expvar.Int),
+		nodeChanges: new(expvar.Map).Init(),
+		movements:   new(expvar.Map).Init(),
+	}
+	ring, err := New(WithMetrics[RingPayloadType](metrics))
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+	ring.Remove("1")
+
+	require.Equal(t, int64(1), metrics.emplaces.Value())
+	require.Equal(t, int64(1), metrics.removes.Value())
+	require.Equal(t, "1", metrics.nodeChanges.Get("A").String())
+	require.Nil(t, metrics.movements.Get("A"))
+}
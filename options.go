@@ -0,0 +1,65 @@
+package ring
+
+import "reflect"
+
+// WithHash provides an option for New which sets the function hashing keys and slices onto the ring.
+func WithHash[T any](h func(string) uint64) func(*Ring[T]) {
+	return func(r *Ring[T]) {
+		r.Hash = h
+	}
+}
+
+// WithBaseVFactor provides an option for New which sets the number of slices per unit of a node's VFactor.
+func WithBaseVFactor[T any](n int) func(*Ring[T]) {
+	return func(r *Ring[T]) {
+		r.BaseVFactor = n
+	}
+}
+
+// WithFilter provides an option for New which sets the function deriving the registration matched by each op,
+// both when registering watchers and when notifying them.
+func WithFilter[T any](f func(Op[T]) string) func(*Ring[T]) {
+	return func(r *Ring[T]) {
+		r.Filter = f
+	}
+}
+
+// PayloadFilter provides a filter for WithFilter which derives the registration of each op from its payload,
+// so that watchers can be routed by hints carried in their payloads. Watchers are registered with an op carrying
+// a payload from which the same registration is derived. Nil payloads, such as a nil pointer, are never passed to
+// the function, and instead derive the empty registration, as do all payloads when the function is nil.
+func PayloadFilter[T any](f func(T) string) func(Op[T]) string {
+	return func(op Op[T]) string {
+		if f == nil || isNil(op.Payload) {
+			return ""
+		}
+
+		return f(op.Payload)
+	}
+}
+
+// isNil reports whether the value is nil, for any type which can be nil.
+func isNil[T any](value T) bool {
+	v := reflect.ValueOf(&value).Elem()
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// WithMetrics provides an option for New which sets the Metrics recording counters of the ring's activity.
+func WithMetrics[T any](m Metrics) func(*Ring[T]) {
+	return func(r *Ring[T]) {
+		r.Metrics = m
+	}
+}
+
+// WithSalts provides an option for New which sets the salts prepended to slice names and hash keys before hashing.
+func WithSalts[T any](sliceSalt, keySalt string) func(*Ring[T]) {
+	return func(r *Ring[T]) {
+		r.SliceSalt = sliceSalt
+		r.KeySalt = keySalt
+	}
+}
@@ -0,0 +1,87 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions(t *testing.T) {
+	ring, err := New(
+		WithHash[RingPayloadType](Murmur3),
+		WithBaseVFactor[RingPayloadType](4),
+		WithFilter(func(o Op[RingPayloadType]) string {
+			return o.Key
+		}),
+		// Raw options can be mixed with the option builders.
+		func(r *Ring[RingPayloadType]) {
+			r.WatcherBuffer = 1
+		},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, Murmur3("key"), ring.HashKey("key"))
+	require.Equal(t, 4, ring.BaseVFactor)
+	require.Equal(t, 1, ring.WatcherBuffer)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+	require.Len(t, ring.slices, 4)
+
+	// Watchers are registered and notified by key.
+	c := ring.RegisterWatcher(Op[RingPayloadType]{Key: "1"})
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+	require.Equal(t, Op[RingPayloadType]{Key: "1", Node: "A"}, <-c)
+}
+
+func TestWithBaseVFactorInvalid(t *testing.T) {
+	_, err := New(WithBaseVFactor[RingPayloadType](0))
+	require.ErrorIs(t, err, ErrInvalidBaseVFactor)
+}
+
+type routedPayload struct {
+	Region string
+}
+
+func TestPayloadFilter(t *testing.T) {
+	filter := PayloadFilter(func(p *routedPayload) string {
+		return p.Region
+	})
+
+	// The registration op of a watcher which ignores payloads has a nil payload.
+	require.Empty(t, filter(Op[*routedPayload]{Node: "A"}))
+	require.Empty(t, PayloadFilter[*routedPayload](nil)(Op[*routedPayload]{Payload: &routedPayload{Region: "east"}}))
+
+	ring, err := New(WithFilter(filter), func(r *Ring[*routedPayload]) {
+		r.WatcherBuffer = 4
+	})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	east := ring.RegisterWatcher(Op[*routedPayload]{Payload: &routedPayload{Region: "east"}})
+	unrouted := ring.RegisterWatcher(Op[*routedPayload]{})
+
+	for _, key := range []*Key[*routedPayload]{
+		{InnerKey: &InnerKey{Key: "1"}, Value: &routedPayload{Region: "east"}},
+		{InnerKey: &InnerKey{Key: "2"}, Value: &routedPayload{Region: "west"}},
+		{InnerKey: &InnerKey{Key: "3"}},
+	} {
+		err = ring.Emplace(key)
+		require.NoError(t, err)
+	}
+	ring.Remove("1")
+
+	require.Len(t, east, 2)
+	op := <-east
+	require.Equal(t, "1", op.Key)
+	require.False(t, op.Removed)
+	op = <-east
+	require.Equal(t, "1", op.Key)
+	require.True(t, op.Removed)
+
+	require.Len(t, unrouted, 1)
+	require.Equal(t, Op[*routedPayload]{Key: "3", Node: "A"}, <-unrouted)
+}
@@ -0,0 +1,132 @@
+package ring
+
+import "sort"
+
+// Pin forces the key onto the node with the provided identifier, regardless of its hash. Watchers are notified
+// of the key's removal from its previous node and its addition to the pinned node. The key stays on the pinned
+// node through every subsequent change to the ring until it is unpinned, removed, or the node is deleted, in
+// which case the key returns to its hash-based placement. Pins are part of snapshots and the JSON encoding of the
+// ring, but not of states.
+func (ring *Ring[T]) Pin(key string, node string) error {
+	defer ring.timeOp("Pin")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	hash, ok := ring.hashesByKey[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	_, ok = ring.vFactorByNode[node]
+	if !ok {
+		return ErrNodeNotFound
+	}
+
+	prev := ring.ownerOfKey(key, hash)
+	ring.pinnedKeys[key] = node
+	ring.publishKey(key)
+	ring.notifyMove(key, prev, owner{node: node})
+
+	return nil
+}
+
+// Unpin returns the key to its hash-based placement, notifying watchers if it changes nodes.
+// It is a noop if the key is not pinned.
+func (ring *Ring[T]) Unpin(key string) {
+	defer ring.timeOp("Unpin")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	node, ok := ring.pinnedKeys[key]
+	if !ok {
+		return
+	}
+
+	delete(ring.pinnedKeys, key)
+	ring.publishKey(key)
+	ring.notifyMove(key, owner{node: node}, ring.ownerOfHash(ring.hashesByKey[key]))
+}
+
+// unpinNode unpins every key pinned to the node with the provided identifier, in sorted order, returning each
+// key to its hash-based placement.
+func (ring *Ring[T]) unpinNode(identifier string) {
+	var keys []string
+	for key, node := range ring.pinnedKeys {
+		if node == identifier {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		delete(ring.pinnedKeys, key)
+		ring.publishKey(key)
+		ring.notifyMove(key, owner{node: identifier}, ring.ownerOfHash(ring.hashesByKey[key]))
+	}
+}
+
+// notifyMove notifies the removal of the key from its previous owner and its addition to the next owner,
+// skipping parked owners. It is a noop if the owners are the same.
+func (ring *Ring[T]) notifyMove(key string, prev owner, next owner) {
+	if prev == next {
+		return
+	}
+
+	if !prev.parked {
+		ring.notify(Op[T]{
+			Key:        key,
+			Payload:    ring.contentByKey[key],
+			Node:       prev.node,
+			Removed:    true,
+			RingChange: true,
+		})
+	}
+
+	if !next.parked {
+		ring.Metrics.IncMovement(next.node)
+		ring.notify(Op[T]{
+			Key:        key,
+			Payload:    ring.contentByKey[key],
+			Node:       next.node,
+			RingChange: true,
+		})
+	}
+}
+
+// unpinnedKeys lists the keys of the hash which are not pinned, and so move with the hash.
+func (ring *Ring[T]) unpinnedKeys(hash uint64) []*InnerKey {
+	keys := ring.keysByHash[hash]
+	if len(ring.pinnedKeys) == 0 {
+		return keys
+	}
+
+	unpinned := make([]*InnerKey, 0, len(keys))
+	for _, key := range keys {
+		_, ok := ring.pinnedKeys[key.Key]
+		if !ok {
+			unpinned = append(unpinned, key)
+		}
+	}
+
+	return unpinned
+}
+
+// pinnedSlice resolves the closest slice of the node preceding the hash, which governs a key pinned to the node.
+// The hash itself is used if the node has no slices.
+func (ring *Ring[T]) pinnedSlice(node string, hash uint64) uint64 {
+	if len(ring.slices) == 0 {
+		return hash
+	}
+
+	idx := findPrevIndex(ring.slices, findIndex(ring.slices, hash))
+	for range ring.slices {
+		if ring.nodesBySlice[ring.slices[idx]] == node {
+			return ring.slices[idx]
+		}
+		idx = findPrevIndex(ring.slices, idx)
+	}
+
+	return hash
+}
@@ -0,0 +1,150 @@
+package ring
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func pinnedRing(t *testing.T) (*Ring[RingPayloadType], string, string) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	for _, identifier := range []string{"A", "B"} {
+		err = ring.CreateNode(Node{Identifier: identifier, VFactor: 2})
+		require.NoError(t, err)
+	}
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "key"}})
+	require.NoError(t, err)
+
+	prev, err := ring.GetNodeForKey("key")
+	require.NoError(t, err)
+
+	pinned := "A"
+	if prev == "A" {
+		pinned = "B"
+	}
+
+	return ring, prev, pinned
+}
+
+func TestPin(t *testing.T) {
+	ring, prev, pinned := pinnedRing(t)
+
+	ring.WatcherBuffer = 1
+	from := ring.RegisterWatcher(Op[RingPayloadType]{Node: prev})
+	to := ring.RegisterWatcher(Op[RingPayloadType]{Node: pinned})
+
+	err := ring.Pin("key", pinned)
+	require.NoError(t, err)
+
+	require.Equal(t, Op[RingPayloadType]{Key: "key", Node: prev, Removed: true, RingChange: true}, <-from)
+	require.Equal(t, Op[RingPayloadType]{Key: "key", Node: pinned, RingChange: true}, <-to)
+
+	node, err := ring.GetNodeForKey("key")
+	require.NoError(t, err)
+	require.Equal(t, pinned, node)
+
+	routed, slice, _, err := ring.Route("key")
+	require.NoError(t, err)
+	require.Equal(t, pinned, routed)
+	require.Contains(t, ring.ListSlicesForNode(pinned), slice)
+
+	require.ErrorIs(t, ring.Pin("missing", pinned), ErrKeyNotFound)
+	require.ErrorIs(t, ring.Pin("key", "C"), ErrNodeNotFound)
+}
+
+func TestPinSurvivesCreateNode(t *testing.T) {
+	ring, _, pinned := pinnedRing(t)
+
+	err := ring.Pin("key", pinned)
+	require.NoError(t, err)
+
+	c := ring.RegisterWatcher(Op[RingPayloadType]{Node: pinned})
+	ring.WatcherBuffer = 100
+	all := ring.WatchAll()
+
+	for idx := 0; idx < 20; idx++ {
+		err = ring.CreateNode(Node{Identifier: strconv.Itoa(idx), VFactor: 4})
+		require.NoError(t, err)
+	}
+
+	node, err := ring.GetNodeForKey("key")
+	require.NoError(t, err)
+	require.Equal(t, pinned, node)
+	require.Empty(t, c)
+	require.Empty(t, all)
+
+	ops, err := ring.NodeSnapshot(pinned)
+	require.NoError(t, err)
+	require.Equal(t, []Op[RingPayloadType]{{Key: "key", Node: pinned}}, ops)
+}
+
+func TestUnpin(t *testing.T) {
+	ring, prev, pinned := pinnedRing(t)
+
+	err := ring.Pin("key", pinned)
+	require.NoError(t, err)
+
+	ring.WatcherBuffer = 1
+	from := ring.RegisterWatcher(Op[RingPayloadType]{Node: pinned})
+	to := ring.RegisterWatcher(Op[RingPayloadType]{Node: prev})
+
+	ring.Unpin("key")
+
+	require.Equal(t, Op[RingPayloadType]{Key: "key", Node: pinned, Removed: true, RingChange: true}, <-from)
+	require.Equal(t, Op[RingPayloadType]{Key: "key", Node: prev, RingChange: true}, <-to)
+
+	node, err := ring.GetNodeForKey("key")
+	require.NoError(t, err)
+	require.Equal(t, prev, node)
+
+	// Unpinning an unpinned key is a noop.
+	ring.Unpin("key")
+	require.Empty(t, from)
+	require.Empty(t, to)
+}
+
+func TestDeletePinnedNode(t *testing.T) {
+	ring, prev, pinned := pinnedRing(t)
+
+	err := ring.Pin("key", pinned)
+	require.NoError(t, err)
+
+	ring.WatcherBuffer = 1
+	from := ring.RegisterWatcher(Op[RingPayloadType]{Node: pinned})
+	to := ring.RegisterWatcher(Op[RingPayloadType]{Node: prev})
+
+	ring.DeleteNode(pinned)
+
+	require.Equal(t, Op[RingPayloadType]{Key: "key", Node: pinned, Removed: true, RingChange: true}, <-from)
+	require.Equal(t, Op[RingPayloadType]{Key: "key", Node: prev, RingChange: true}, <-to)
+
+	node, err := ring.GetNodeForKey("key")
+	require.NoError(t, err)
+	require.Equal(t, prev, node)
+}
+
+func TestRemovePinnedKey(t *testing.T) {
+	ring, prev, pinned := pinnedRing(t)
+
+	err := ring.Pin("key", pinned)
+	require.NoError(t, err)
+
+	ring.WatcherBuffer = 1
+	c := ring.RegisterWatcher(Op[RingPayloadType]{Node: pinned})
+
+	ring.Remove("key")
+	require.Equal(t, Op[RingPayloadType]{Key: "key", Node: pinned, Removed: true}, <-c)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "key"}})
+	require.NoError(t, err)
+	require.Empty(t, c)
+
+	// The pin is dropped with the key.
+	node, err := ring.GetNodeForKey("key")
+	require.NoError(t, err)
+	require.Equal(t, prev, node)
+}
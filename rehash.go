@@ -0,0 +1,182 @@
+package ring
+
+// PreviewRehash reports how many keys would change owners if the ring's Hash were replaced with the new hash,
+// by rehashing a clone of the ring. The ring itself is left unchanged. ErrSliceHashCollision is returned if two
+// slices would collide under the new hash.
+func (ring *Ring[T]) PreviewRehash(newHash func(string) uint64) (moved int, err error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	clone, err := ring.rehashedClone(newHash)
+	if err != nil {
+		return 0, err
+	}
+
+	for key := range ring.hashesByKey {
+		prev, _ := ring.nodeForKey(key)
+		next, _ := clone.nodeForKey(key)
+		if prev != next {
+			moved++
+		}
+	}
+
+	return moved, nil
+}
+
+// Rehash replaces the ring's Hash with the new hash, recomputing the hash of every slice and key, so that the ring
+// remains consistent. Watchers are notified of each key which changes owners, in ascending order of the new hashes,
+// and event streams observe every previous slice being removed before every new slice is added. The order of the
+// keys sharing a hash is preserved. ErrSliceHashCollision is returned, leaving the ring unchanged, if two slices
+// would collide under the new hash.
+func (ring *Ring[T]) Rehash(newHash func(string) uint64) error {
+	defer ring.timeOp("Rehash")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	prevHash := ring.Hash
+	ring.Hash = newHash
+
+	s, err := ring.rehash(ring)
+	if err != nil {
+		ring.Hash = prevHash
+		return err
+	}
+
+	prevOwners := make(map[string]owner, len(ring.hashesByKey))
+	for key, hash := range ring.hashesByKey {
+		prevOwners[key] = ring.ownerOfKey(key, hash)
+	}
+
+	// Restoring resets the touches and expiries of the keys, which are unaffected by their hashes.
+	touchedByKey, expiryByKey, expiries := ring.touchedByKey, ring.expiryByKey, ring.expiries
+	prevSlices, prevNodesBySlice := ring.slices, ring.nodesBySlice
+
+	err = ring.restore(s)
+	if err != nil {
+		ring.Hash = prevHash
+		return err
+	}
+	ring.touchedByKey, ring.expiryByKey, ring.expiries = touchedByKey, expiryByKey, expiries
+
+	for _, slice := range prevSlices {
+		ring.emit(SliceEvent{Slice: slice, Node: prevNodesBySlice[slice], Removed: true})
+	}
+
+	for _, slice := range ring.slices {
+		ring.emit(SliceEvent{Slice: slice, Node: ring.nodesBySlice[slice]})
+	}
+
+	for _, hash := range ring.hashes {
+		for _, key := range ring.keysByHash[hash] {
+			prev, next := prevOwners[key.Key], ring.ownerOfKey(key.Key, hash)
+			if prev == next {
+				continue
+			}
+
+			if !prev.parked {
+				ring.notify(Op[T]{
+					Key:        key.Key,
+					Payload:    ring.contentByKey[key.Key],
+					Node:       prev.node,
+					Removed:    true,
+					RingChange: true,
+				})
+			}
+
+			if !next.parked {
+				ring.Metrics.IncMovement(next.node)
+				ring.notify(Op[T]{
+					Key:        key.Key,
+					Payload:    ring.contentByKey[key.Key],
+					Node:       next.node,
+					RingChange: true,
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// rehashedClone creates a copy of the ring in which every slice and key is hashed with the new hash instead.
+// Watchers and event streams are not copied, so changes to the clone are not observed.
+func (ring *Ring[T]) rehashedClone(newHash func(string) uint64) (*Ring[T], error) {
+	clone, err := New(func(r *Ring[T]) {
+		r.Hash = newHash
+		r.BaseVFactor = ring.BaseVFactor
+		r.ToSliceName = ring.ToSliceName
+		r.SliceSalt = ring.SliceSalt
+		r.KeySalt = ring.KeySalt
+		r.IndexBy = ring.IndexBy
+		r.Clock = ring.Clock
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The clone is never looked up, so its topology view is never published.
+	clone.noView = true
+	s, err := clone.rehash(ring)
+	if err != nil {
+		return nil, err
+	}
+
+	err = clone.restore(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
+
+// rehash builds a snapshot of the source ring in which every slice and key is hashed by this ring instead.
+// The order of the keys sharing a hash and the pins of the keys are preserved, and ErrSliceHashCollision is
+// returned if any slices collide.
+func (ring *Ring[T]) rehash(source *Ring[T]) (*snapshot[T], error) {
+	s := &snapshot[T]{
+		VFactorByNode: make(map[string]int, len(source.vFactorByNode)),
+		ZoneByNode:    make(map[string]string, len(source.zoneByNode)),
+		NodesBySlice:  make(map[uint64]string, len(source.nodesBySlice)),
+		Keys:          make([]snapshotKey[T], 0, len(source.hashesByKey)),
+		PinnedKeys:    make(map[string]string, len(source.pinnedKeys)),
+	}
+	for key, node := range source.pinnedKeys {
+		s.PinnedKeys[key] = node
+	}
+
+	for node, vFactor := range source.vFactorByNode {
+		s.VFactorByNode[node] = vFactor
+		for idx := 0; idx < vFactor*ring.BaseVFactor; idx++ {
+			slice := ring.HashSlice(node, idx)
+
+			_, ok := s.NodesBySlice[slice]
+			if ok {
+				return nil, ErrSliceHashCollision
+			}
+			s.NodesBySlice[slice] = node
+		}
+	}
+	for node, zone := range source.zoneByNode {
+		s.ZoneByNode[node] = zone
+	}
+
+	for _, hash := range source.hashes {
+		for _, key := range source.keysByHash[hash] {
+			hashKey, ok := source.hashKeyByKey[key.Key]
+			if !ok {
+				hashKey = key.Key
+			}
+
+			s.Keys = append(s.Keys, snapshotKey[T]{
+				Key:     key.Key,
+				Order:   key.Order,
+				Hash:    ring.HashKey(hashKey),
+				HashKey: hashKey,
+				Payload: source.contentByKey[key.Key],
+			})
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,128 @@
+package ring
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func populatedRehashRing(t *testing.T, hash func(string) uint64) *Ring[RingPayloadType] {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.Hash = hash
+		r.BaseVFactor = 3
+	})
+	require.NoError(t, err)
+
+	for _, identifier := range []string{"A", "B", "C"} {
+		err = ring.CreateNode(Node{Identifier: identifier, VFactor: 2})
+		require.NoError(t, err)
+	}
+
+	for idx := 0; idx < 100; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{
+			InnerKey: &InnerKey{Key: strconv.Itoa(idx)},
+		}, strconv.Itoa(idx%40))
+		require.NoError(t, err)
+	}
+
+	return ring
+}
+
+func TestPreviewRehash(t *testing.T) {
+	ring := populatedRehashRing(t, MD5)
+	expected := populatedRehashRing(t, FNV1a)
+
+	moved := 0
+	for idx := 0; idx < 100; idx++ {
+		prev, _ := ring.nodeForKey(strconv.Itoa(idx))
+		next, _ := expected.nodeForKey(strconv.Itoa(idx))
+		if prev != next {
+			moved++
+		}
+	}
+	require.Positive(t, moved)
+
+	state := ring.TopologyState()
+
+	previewed, err := ring.PreviewRehash(FNV1a)
+	require.NoError(t, err)
+	require.Equal(t, moved, previewed)
+
+	// The ring itself is unchanged.
+	require.Equal(t, state, ring.TopologyState())
+
+	previewed, err = ring.PreviewRehash(MD5)
+	require.NoError(t, err)
+	require.Zero(t, previewed)
+}
+
+func TestPreviewRehashCollision(t *testing.T) {
+	ring := populatedRehashRing(t, MD5)
+
+	_, err := ring.PreviewRehash(func(string) uint64 { return 0 })
+	require.ErrorIs(t, err, ErrSliceHashCollision)
+}
+
+func TestRehashedClonePreservesOrder(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	for _, key := range []*InnerKey{{Key: "b", Order: 1}, {Key: "a", Order: 0}} {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: key}, "shared")
+		require.NoError(t, err)
+	}
+
+	clone, err := ring.rehashedClone(FNV1a)
+	require.NoError(t, err)
+	require.Equal(t, map[uint64][]string{
+		FNV1a("shared"): {"a", "b"},
+	}, clone.OrderingReport())
+}
+
+func TestRehash(t *testing.T) {
+	ring := populatedRehashRing(t, MD5)
+	expected := populatedRehashRing(t, FNV1a)
+
+	moved, err := ring.PreviewRehash(FNV1a)
+	require.NoError(t, err)
+
+	// Every moved key is removed from one node and added to another.
+	ring.WatcherBuffer = 2 * moved
+	var watchers []chan Op[RingPayloadType]
+	for _, identifier := range []string{"A", "B", "C"} {
+		watchers = append(watchers, ring.RegisterWatcher(Op[RingPayloadType]{Node: identifier}))
+	}
+
+	err = ring.Rehash(FNV1a)
+	require.NoError(t, err)
+
+	ops := 0
+	for _, c := range watchers {
+		ops += len(c)
+	}
+	require.Equal(t, 2*moved, ops)
+
+	require.Equal(t, expected.State(), ring.State())
+	require.Equal(t, expected.TopologyState(), ring.TopologyState())
+	require.Equal(t, expected.OrderingReport(), ring.OrderingReport())
+	for idx := 0; idx < 100; idx++ {
+		node, err := ring.GetNodeForKey(strconv.Itoa(idx))
+		require.NoError(t, err)
+
+		lookup, ok := ring.Lookup(strconv.Itoa(idx % 40))
+		require.True(t, ok)
+		require.Equal(t, lookup, node)
+	}
+}
+
+func TestRehashCollision(t *testing.T) {
+	ring := populatedRehashRing(t, MD5)
+	state := ring.State()
+
+	err := ring.Rehash(func(string) uint64 { return 0 })
+	require.ErrorIs(t, err, ErrSliceHashCollision)
+
+	require.Equal(t, state, ring.State())
+	require.Equal(t, MD5("key"), ring.HashKey("key"))
+}
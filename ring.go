@@ -10,9 +10,16 @@
 package ring
 
 import (
+	"cmp"
+	"context"
+	"encoding/binary"
 	"fmt"
+	"hash/fnv"
+	"math"
 	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type State struct {
@@ -21,8 +28,24 @@ type State struct {
 	HashesByKey  map[string]uint64 `json:"hashesByKey"`
 }
 
+// SlicePosition is the position of a slice on the ring, as the hash at which the slice begins, and the node owning it.
+type SlicePosition struct {
+	Hash uint64 `json:"hash"`
+	Node string `json:"node"`
+}
+
+// TopologyState describes only the nodes and slices of a ring, excluding every key. It is all that is
+// needed by a client to route keys independently of the ring.
+type TopologyState struct {
+	Slices        []uint64          `json:"slices"`
+	NodesBySlice  map[uint64]string `json:"nodesBySlice"`
+	VFactorByNode map[string]int    `json:"vFactorByNode"`
+	ZoneByNode    map[string]string `json:"zoneByNode,omitempty"`
+}
+
 // Op is a struct describing the movement of a key-value pair of the ring changing --
 // either moving from one slice of the ring to another, being added to the ring, or being removed.
+// Ops removing a key carry the key's final payload, so that filters deriving from payloads match them.
 type Op[T any] struct {
 	Key        string
 	Node       string
@@ -30,20 +53,24 @@ type Op[T any] struct {
 	Removed    bool
 	Updated    bool
 	RingChange bool
+	Tick       bool
 }
 
 // Node is the struct describing a single node of the hash ring, with its corresponding
 // identifier used for hashing and VFactor for creating virtual slices of the node.
+// The optional Zone names the failure domain the node belongs to.
 type Node struct {
 	Identifier string
 	VFactor    int
+	Zone       string
 }
 
 // InnerKey is a struct describing a single, unique key in the system.
 // The key has an associated order property which specifies the order in which notifications
 // occur with respect to the other keys that have hashed to the same position in the ring.
 // A lower order implies that this key will appear in a change notification before the
-// other keys which hash to the same position on the ring.
+// other keys which hash to the same position on the ring. When a single change to the ring
+// moves keys at several positions, the positions are notified in ascending order of their hashes.
 type InnerKey struct {
 	Key   string
 	Order int
@@ -88,69 +115,397 @@ type KeyNodeWatcher[T any] interface {
 	State() *State
 }
 
+// WatcherOptions configures which ops are delivered to a watcher, beyond those matching its filter.
+type WatcherOptions struct {
+	// ChangesOnly restricts the watcher to ops with RingChange set, so that keys being emplaced, updated,
+	// or removed are not delivered, only keys moving between nodes as the topology of the ring changes.
+	ChangesOnly bool
+
+	// Lossy drops any op which the watcher isn't ready to receive, rather than blocking changes to the ring
+	// until it is. The number of dropped ops is provided by DroppedOps.
+	Lossy bool
+}
+
 type opChans[T any] struct {
-	msg  chan Op[T]
-	done chan struct{}
-	wg   *sync.WaitGroup
+	msg     chan Op[T]
+	done    chan struct{}
+	wg      *sync.WaitGroup
+	options WatcherOptions
+	dropped *atomic.Uint64
+	now     func() time.Time
+
+	// accepted is when the watcher last accepted an op, in nanoseconds, and undelivered reports whether an op has
+	// been waiting or dropped since.
+	accepted    *atomic.Int64
+	undelivered *atomic.Bool
 }
 
 type watcher[T any] struct {
 	watchMu  sync.Mutex
-	watchers map[string]opChans[T]
+	watchers map[string][]*opChans[T]
+	all      []*opChans[T]
 	Filter   func(Op[T]) string
+
+	// WatcherBuffer is the capacity of the channel of each watcher registered afterwards, allowing bursts of ops
+	// to be delivered without waiting on the consumer. Ops are still delivered in order, so once the buffer of a
+	// watcher is full, changes to the ring block on the consumer as they do for an unbuffered watcher.
+	WatcherBuffer int
+
+	// NotifyTimeout bounds how long delivering an op blocks on a watcher which isn't ready to receive it, after
+	// which the op is dropped and counted by DroppedOps, so that a stuck watcher cannot wedge the ring.
+	// Delivery blocks indefinitely when zero.
+	NotifyTimeout time.Duration
+
+	// now provides the current time when watchers accept ops, defaulting to the wall clock when nil.
+	now func() time.Time
 }
 
 // RegisterWatcher provides a channel of Ops for any key-value changes of an inserted node.
 // If the node registered does not exist, no notifications will come through until that node
-// is inserted into the ring.
+// is inserted into the ring. Any number of watchers may share the same filter, each receiving
+// every matching op on its own channel.
 func (ring *watcher[T]) RegisterWatcher(filter Op[T]) chan Op[T] {
+	return ring.RegisterWatcherWithOptions(filter, WatcherOptions{})
+}
+
+// RegisterWatcherWithOptions provides a channel of Ops like RegisterWatcher, delivering only the ops
+// permitted by the options.
+func (ring *watcher[T]) RegisterWatcherWithOptions(filter Op[T], options WatcherOptions) chan Op[T] {
+	return ring.register(filter, options).msg
+}
+
+// RegisterWatcherCtx provides a channel of Ops like RegisterWatcher, which is deregistered and closed
+// once the context is done, unless it has already been deregistered.
+func (ring *watcher[T]) RegisterWatcherCtx(ctx context.Context, filter Op[T]) chan Op[T] {
+	opChans := ring.register(filter, WatcherOptions{})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			ring.DeregisterWatcherChannel(opChans.msg)
+		case <-opChans.done:
+		}
+	}()
+
+	return opChans.msg
+}
+
+func (ring *watcher[T]) register(filter Op[T], options WatcherOptions) *opChans[T] {
+	return ring.registerBuffered(filter, options, ring.WatcherBuffer)
+}
+
+// registerBuffered registers a watcher whose channel has the capacity of the buffer.
+func (ring *watcher[T]) registerBuffered(filter Op[T], options WatcherOptions, buffer int) *opChans[T] {
 	ring.watchMu.Lock()
 	defer ring.watchMu.Unlock()
-	opChans := opChans[T]{
-		msg:  make(chan Op[T]),
-		done: make(chan struct{}),
-		wg:   new(sync.WaitGroup),
-	}
-	ring.watchers[ring.Filter(filter)] = opChans
+	opChans := newOpChans[T](options, buffer, ring.clock())
+	key := ring.Filter(filter)
+	ring.watchers[key] = append(ring.watchers[key], opChans)
+	return opChans
+}
+
+// WatchAll provides a channel of every op, regardless of the filter derived from each op, in addition to
+// any watchers registered with a matching filter. Each op is delivered to those watchers first.
+func (ring *watcher[T]) WatchAll() chan Op[T] {
+	ring.watchMu.Lock()
+	defer ring.watchMu.Unlock()
+	opChans := newOpChans[T](WatcherOptions{}, ring.WatcherBuffer, ring.clock())
+	ring.all = append(ring.all, opChans)
 	return opChans.msg
 }
 
-// DeregisterWatcher attempts to close the channel and delete the registration from memory.
-// It is a noop if the watcher does not exist.
+// UnwatchAll attempts to close a channel provided by WatchAll and delete its registration from memory.
+// It is a noop if the channel is not registered.
+func (ring *watcher[T]) UnwatchAll(c chan Op[T]) {
+	ring.watchMu.Lock()
+
+	var found *opChans[T]
+	for idx, watcher := range ring.all {
+		if watcher.msg == c {
+			found = watcher
+			ring.all, _ = removeIndex(ring.all, idx)
+			break
+		}
+	}
+	ring.watchMu.Unlock()
+
+	if found != nil {
+		found.close()
+	}
+}
+
+// DeregisterWatcher attempts to close the channels of every watcher registered with the filter and
+// delete the registrations from memory. It is a noop if no such watcher exists.
 func (ring *watcher[T]) DeregisterWatcher(op Op[T]) {
 	ring.watchMu.Lock()
 
 	filter := ring.Filter(op)
-	c, ok := ring.watchers[filter]
-	if !ok {
-		ring.watchMu.Unlock()
-		return
-	}
+	watchers := ring.watchers[filter]
 	delete(ring.watchers, filter)
 	ring.watchMu.Unlock()
 
+	for _, c := range watchers {
+		c.close()
+	}
+}
+
+// DeregisterWatcherChannel attempts to close the channel of a single watcher, as returned at registration,
+// and delete its registration from memory, leaving any other watchers sharing its filter registered.
+// It is a noop if the channel is not registered.
+func (ring *watcher[T]) DeregisterWatcherChannel(c chan Op[T]) {
+	ring.watchMu.Lock()
+
+	var found *opChans[T]
+	for filter, watchers := range ring.watchers {
+		for idx, watcher := range watchers {
+			if watcher.msg != c {
+				continue
+			}
+
+			found = watcher
+			watchers, _ = removeIndex(watchers, idx)
+			if len(watchers) == 0 {
+				delete(ring.watchers, filter)
+			} else {
+				ring.watchers[filter] = watchers
+			}
+			break
+		}
+		if found != nil {
+			break
+		}
+	}
+	ring.watchMu.Unlock()
+
+	if found != nil {
+		found.close()
+	}
+}
+
+// DroppedOps provides the number of ops dropped by the watchers currently registered with the filter, either as
+// they are lossy or as the NotifyTimeout elapsed.
+func (ring *watcher[T]) DroppedOps(filter Op[T]) uint64 {
+	ring.watchMu.Lock()
+	defer ring.watchMu.Unlock()
+
+	var dropped uint64
+	for _, watcher := range ring.watchers[ring.Filter(filter)] {
+		dropped += watcher.dropped.Load()
+	}
+
+	return dropped
+}
+
+// PruneStaleWatchers deregisters and closes every watcher, including those provided by WatchAll, which hasn't
+// accepted any op within the window although an op has since waited on it or been dropped, whether as it is lossy
+// or as the NotifyTimeout elapsed, and whose buffer is still full, as its consumer has likely abandoned it.
+// Watchers are considered to have accepted an op when registered, so idle watchers are never pruned. It returns
+// the number of watchers pruned, and is intended to be called periodically.
+func (ring *watcher[T]) PruneStaleWatchers(olderThan time.Duration) int {
+	ring.watchMu.Lock()
+
+	cutoff := ring.clock()().Add(-olderThan).UnixNano()
+	stale := func(c *opChans[T]) bool {
+		return c.undelivered.Load() && c.accepted.Load() < cutoff && len(c.msg) == cap(c.msg)
+	}
+
+	var pruned []*opChans[T]
+	for filter, watchers := range ring.watchers {
+		live := watchers[:0]
+		for _, watcher := range watchers {
+			if stale(watcher) {
+				pruned = append(pruned, watcher)
+				continue
+			}
+			live = append(live, watcher)
+		}
+
+		if len(live) == 0 {
+			delete(ring.watchers, filter)
+		} else {
+			ring.watchers[filter] = live
+		}
+	}
+
+	live := ring.all[:0]
+	for _, watcher := range ring.all {
+		if stale(watcher) {
+			pruned = append(pruned, watcher)
+			continue
+		}
+		live = append(live, watcher)
+	}
+	ring.all = live
+	ring.watchMu.Unlock()
+
+	for _, watcher := range pruned {
+		watcher.close()
+	}
+
+	return len(pruned)
+}
+
+// clock provides the function giving the current time to watchers.
+func (ring *watcher[T]) clock() func() time.Time {
+	if ring.now == nil {
+		return time.Now
+	}
+
+	return ring.now
+}
+
+func newOpChans[T any](options WatcherOptions, buffer int, now func() time.Time) *opChans[T] {
+	c := &opChans[T]{
+		msg:         make(chan Op[T], buffer),
+		done:        make(chan struct{}),
+		wg:          new(sync.WaitGroup),
+		options:     options,
+		dropped:     new(atomic.Uint64),
+		now:         now,
+		accepted:    new(atomic.Int64),
+		undelivered: new(atomic.Bool),
+	}
+	c.accepted.Store(now().UnixNano())
+
+	return c
+}
+
+// accept records that the watcher accepted an op.
+func (c *opChans[T]) accept() {
+	c.accepted.Store(c.now().UnixNano())
+	c.undelivered.Store(false)
+}
+
+// send delivers the op to the watcher, giving up if the watcher is deregistered or the cancel channel is closed.
+// Lossy watchers are only sent the op if they are ready to receive it, and otherwise the op is dropped once the
+// timeout elapses, unless it is zero.
+func (c *opChans[T]) send(op Op[T], cancel <-chan struct{}, timeout time.Duration) {
+	if c.options.Lossy {
+		select {
+		case c.msg <- op:
+			c.accept()
+		default:
+			c.undelivered.Store(true)
+			c.dropped.Add(1)
+		}
+		return
+	}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	// Deliver at once if the watcher is ready, so that the op is only considered undelivered while it is not.
+	select {
+	case c.msg <- op:
+		c.accept()
+		return
+	default:
+	}
+
+	c.undelivered.Store(true)
+
+	select {
+	case c.msg <- op:
+		c.accept()
+	case <-c.done:
+	case <-cancel:
+	case <-expired:
+		c.dropped.Add(1)
+	}
+}
+
+// close closes the channel once every notification in progress has given up on it.
+func (c *opChans[T]) close() {
 	close(c.done)
 	c.wg.Wait()
 	close(c.msg)
 }
 
-func (ring *watcher[T]) notify(op Op[T]) {
+// Rewatch moves every registration matching the old filter so that it matches the new filter instead.
+// The channels returned at registration remain open throughout, so no notifications are missed by
+// the consumers while the move occurs.
+func (ring *watcher[T]) Rewatch(old, new Op[T]) error {
 	ring.watchMu.Lock()
-	watcher, ok := ring.watchers[ring.Filter(op)]
+	defer ring.watchMu.Unlock()
+
+	oldFilter := ring.Filter(old)
+	watchers, ok := ring.watchers[oldFilter]
 	if !ok {
-		ring.watchMu.Unlock()
-		return
+		return ErrWatcherNotFound
+	}
+
+	newFilter := ring.Filter(new)
+	if newFilter == oldFilter {
+		return nil
+	}
+
+	// Refuse to merge into another consumer's registration.
+	_, ok = ring.watchers[newFilter]
+	if ok {
+		return ErrWatcherAlreadyExists
+	}
+
+	delete(ring.watchers, oldFilter)
+	ring.watchers[newFilter] = watchers
+
+	return nil
+}
+
+func (ring *watcher[T]) notify(op Op[T]) {
+	ring.watchMu.Lock()
+	var watchers []*opChans[T]
+	for _, watcher := range ring.watchers[ring.Filter(op)] {
+		if watcher.options.ChangesOnly && !op.RingChange {
+			continue
+		}
+		watcher.wg.Add(1)
+		watchers = append(watchers, watcher)
+	}
+	for _, watcher := range ring.all {
+		watcher.wg.Add(1)
+		watchers = append(watchers, watcher)
 	}
-	watcher.wg.Add(1)
-	defer watcher.wg.Done()
 	ring.watchMu.Unlock()
 
-	select {
-	case watcher.msg <- op:
-	case <-watcher.done:
+	for _, watcher := range watchers {
+		watcher.send(op, nil, ring.NotifyTimeout)
+		watcher.wg.Done()
+	}
+}
+
+// broadcast sends the op to every registered watcher regardless of filter, giving up on any watcher
+// still blocking when the cancel channel is closed.
+func (ring *watcher[T]) broadcast(op Op[T], cancel <-chan struct{}) {
+	ring.watchMu.Lock()
+	watchers := make([]*opChans[T], 0, len(ring.watchers)+len(ring.all))
+	for _, registered := range ring.watchers {
+		for _, watcher := range registered {
+			watcher.wg.Add(1)
+			watchers = append(watchers, watcher)
+		}
+	}
+	for _, watcher := range ring.all {
+		watcher.wg.Add(1)
+		watchers = append(watchers, watcher)
+	}
+	ring.watchMu.Unlock()
+
+	for _, watcher := range watchers {
+		watcher.send(op, cancel, ring.NotifyTimeout)
+		watcher.wg.Done()
 	}
 }
 
+// defaultAutoBalanceMaxVFactor is the default largest VFactor which AutoBalance grows a node to.
+const defaultAutoBalanceMaxVFactor = 16
+
+// convertProgressInterval is the number of hashes reassigned between calls to OnConvertProgress.
+const convertProgressInterval = 1024
+
 // Ring is a hash ring implementation capable of storing key value pairs belonging to member
 // nodes in one or more slices belonging to these nodes. The ring can be observed for changes
 // of the key value pairs (removal, addition, slice changes).
@@ -161,16 +516,104 @@ type Ring[T any] struct {
 
 	nodesBySlice  map[uint64]string
 	vFactorByNode map[string]int
+	zoneByNode    map[string]string
 	slicesByHash  map[uint64]uint64
 	keysByHash    map[uint64][]*InnerKey
+	rankByKey     map[string]keyRank
+	seq           uint64
 	contentByKey  map[string]T
 	hashesByKey   map[string]uint64
+	keysByIndex   map[string][]string
+	retained      *lru[string, T]
+	touchedByKey  map[string]time.Time
+	hashKeyByKey  map[string]string
+	expiryByKey   map[string]time.Time
+	expiries      expiryHeap
+	pinnedKeys    map[string]string
+	moves         map[uint64]owner
+	view          atomic.Pointer[topologyView]
+	keyViews      atomic.Pointer[sync.Map]
+	viewChanges   map[uint64]viewChange
+	viewStale     bool
+	noView        bool
+	converting    bool
+	convertDone   int
+	convertTotal  int
 	mu            sync.RWMutex
 
 	Hash        func(string) uint64
 	BaseVFactor int
 	ToSliceName func(string, int) string
 
+	// IndexBy optionally derives a secondary identifier from each key's payload, which can then
+	// be used to find keys with FindBySecondary. No secondary index is maintained when nil.
+	IndexBy func(T) string
+
+	// RetainRemovedPayloads is the number of most recently removed payloads kept for RestorePayload.
+	// Retention is disabled when zero.
+	RetainRemovedPayloads int
+
+	// OnConvertProgress is optionally invoked periodically while hashes are reassigned between slices
+	// during a topology change, with the number of hashes reassigned so far across every slice of the change
+	// and the total to reassign, which is counted once when the change starts.
+	// It is called while the ring is locked, so it must not call back into the ring.
+	OnConvertProgress func(done, total int)
+
+	// OnRingNonEmpty is optionally invoked once a topology change leaves the ring with slices when it had none,
+	// as keys parked in the empty container move onto the new slices. It is called while the ring is locked,
+	// so it must not call back into the ring.
+	OnRingNonEmpty func()
+
+	// OnRingEmpty is optionally invoked once a topology change leaves the ring with no slices when it had some,
+	// as every key is parked in the empty container. It is called while the ring is locked, so it must not
+	// call back into the ring.
+	OnRingEmpty func()
+
+	// StrictCollisions optionally rejects emplacing a key whose hash is exactly the hash of an existing slice with
+	// ErrKeyHashCollision, so that the key can be emplaced with a different hash key instead.
+	StrictCollisions bool
+
+	// SliceSalt and KeySalt are optionally prepended to the names of slices and to hash keys respectively before
+	// hashing, so that distinct salts place slices and keys in effectively separate hash subspaces which cannot
+	// collide. They are empty by default, preserving the unsalted hashes. They must not change once the ring has
+	// slices or keys.
+	SliceSalt string
+	KeySalt   string
+
+	// MaxMovementFraction optionally caps the fraction of all keys which CreateNode, CreateNodes, UpdateNode,
+	// ResizeNodes, DeleteNodeErr, TryDeleteNode and DrainNode may move, returning ErrMovementExceeded and leaving
+	// the ring unchanged for any change which would move more. The movement is computed against a copy of the ring
+	// before the change, as SimulateCreateNode does. DeleteNode has no error to return, so it is never limited and
+	// always removes the node, as do LoadTopology, Rehash and automatic balancing. It is disabled by default.
+	MaxMovementFraction float64
+
+	// OnOpTiming is optionally invoked once each public mutator returns, with the name of the
+	// mutator and how long it took according to the ring's Clock, including time spent waiting on the lock.
+	OnOpTiming func(op string, dur time.Duration)
+
+	// AutoBalanceMaxVFactor is the largest VFactor which AutoBalance grows a node to.
+	AutoBalanceMaxVFactor int
+
+	// PayloadCodec encodes and decodes payloads when the ring is written to or read from a snapshot.
+	PayloadCodec PayloadCodec[T]
+
+	// Clock provides the current time and tickers to all time dependent features of the ring.
+	Clock Clock
+
+	// Metrics records counters of the ring's activity. By default, nothing is recorded.
+	Metrics Metrics
+
+	heartbeatMu   sync.Mutex
+	heartbeatStop chan struct{}
+	heartbeatDone chan struct{}
+
+	balanceMu   sync.Mutex
+	balanceStop chan struct{}
+	balanceDone chan struct{}
+
+	streamMu sync.Mutex
+	streams  []*eventStream[T]
+
 	watcher[T]
 }
 
@@ -179,22 +622,37 @@ func New[T any](options ...func(*Ring[T])) (*Ring[T], error) {
 	ring := &Ring[T]{
 		nodesBySlice:  make(map[uint64]string),
 		vFactorByNode: make(map[string]int),
+		zoneByNode:    make(map[string]string),
 		slicesByHash:  make(map[uint64]uint64),
 		keysByHash:    make(map[uint64][]*InnerKey),
+		rankByKey:     make(map[string]keyRank),
 		hashesByKey:   make(map[string]uint64),
 		contentByKey:  make(map[string]T),
+		keysByIndex:   make(map[string][]string),
+		touchedByKey:  make(map[string]time.Time),
+		hashKeyByKey:  make(map[string]string),
+		expiryByKey:   make(map[string]time.Time),
+		pinnedKeys:    make(map[string]string),
 		empty:         make(map[uint64]uint64),
 		Hash:          MD5,
 		BaseVFactor:   1,
+		Clock:         systemClock{},
+		Metrics:       noopMetrics{},
+		PayloadCodec:  JSONCodec[T]{},
 		ToSliceName: func(s string, i int) string {
 			return fmt.Sprintf("%s%d", s, i)
 		},
 		watcher: watcher[T]{
-			watchers: make(map[string]opChans[T]),
+			watchers: make(map[string][]*opChans[T]),
 			Filter: func(o Op[T]) string {
 				return o.Node
 			},
 		},
+		AutoBalanceMaxVFactor: defaultAutoBalanceMaxVFactor,
+	}
+
+	ring.now = func() time.Time {
+		return ring.Clock.Now()
 	}
 
 	for _, option := range options {
@@ -206,437 +664,2507 @@ func New[T any](options ...func(*Ring[T])) (*Ring[T], error) {
 		return nil, ErrInvalidBaseVFactor
 	}
 
+	if ring.RetainRemovedPayloads > 0 {
+		ring.retained = newLRU[string, T](ring.RetainRemovedPayloads)
+	}
+	ring.refreshView()
+	ring.publishKeys()
+
 	return ring, nil
 }
 
-func (ring *Ring[T]) State() *State {
-	return &State{
-		NodesBySlice: ring.nodesBySlice,
-		SlicesByHash: ring.slicesByHash,
-		HashesByKey:  ring.hashesByKey,
+// NewWithNodes attempts to create a new ring as New does, and then create each of the provided nodes.
+// It returns the same errors as New and CreateNode.
+func NewWithNodes[T any](nodes []Node, options ...func(*Ring[T])) (*Ring[T], error) {
+	ring, err := New(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ring.CreateNodes(nodes)
+	if err != nil {
+		return nil, err
 	}
+
+	return ring, nil
 }
 
-// CreateNode attempts to add a new node to the hash ring, including all of that nodes associated slices.
-// The nodes VFactor determines how many slices will be associated with the particular node.
-func (ring *Ring[T]) CreateNode(node Node) error {
-	ring.mu.Lock()
-	defer ring.mu.Unlock()
+// HashKey computes the position of a hash key on the ring, exactly as the ring does when emplacing a key.
+func (ring *Ring[T]) HashKey(key string) uint64 {
+	return ring.Hash(ring.KeySalt + key)
+}
 
-	// Check to see if node already exists.
-	_, ok := ring.vFactorByNode[node.Identifier]
-	if ok {
-		return ErrNodeAlreadyExists
-	}
+// HashSlice computes the position on the ring of the virtual slice with the provided index belonging to the
+// node with the provided identifier, exactly as the ring does when creating the node.
+func (ring *Ring[T]) HashSlice(identifier string, idx int) uint64 {
+	return ring.Hash(ring.SliceSalt + ring.ToSliceName(identifier, idx))
+}
 
-	// Save vfactor.
-	ring.vFactorByNode[node.Identifier] = node.VFactor
+// State provides a copy of the ring's slices and keys, which is unaffected by later changes to the ring.
+func (ring *Ring[T]) State() *State {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
 
-	// Create all virtual slices.
-	for idx := 0; idx < node.VFactor*ring.BaseVFactor; idx++ {
+	return ring.state()
+}
 
-		// Compute slice hash and insert slice.
-		slice := ring.Hash(ring.ToSliceName(node.Identifier, idx))
+// state copies the ring's slices and keys.
+func (ring *Ring[T]) state() *State {
+	state := &State{
+		NodesBySlice: make(map[uint64]string, len(ring.nodesBySlice)),
+		SlicesByHash: make(map[uint64]uint64, len(ring.slicesByHash)),
+		HashesByKey:  make(map[string]uint64, len(ring.hashesByKey)),
+	}
 
-		err := ring.insertSlice(slice, node.Identifier)
-		if err != nil {
-			return err
-		}
+	for slice, node := range ring.nodesBySlice {
+		state.NodesBySlice[slice] = node
+	}
+	for hash, slice := range ring.slicesByHash {
+		state.SlicesByHash[hash] = slice
+	}
+	for key, hash := range ring.hashesByKey {
+		state.HashesByKey[key] = hash
 	}
 
-	return nil
+	return state
 }
 
-// DeleteNode attempts to remove a node from the hash ring given the node's identifier.
-// It is a noop if no node with the given identifier exists.
-func (ring *Ring[T]) DeleteNode(identifier string) {
+// LoadState replaces the entire state of the ring with a state previously provided by State. A state does not
+// include payloads or orders, so every key is loaded with an empty payload and an order of zero, and the VFactor of
+// each node is inferred from its number of slices. Watchers are not notified of any keys loaded from the state.
+// ErrInconsistentState is returned, leaving the ring unchanged, if any hash belongs to a slice absent from the state.
+func (ring *Ring[T]) LoadState(state *State) error {
+	defer ring.timeOp("LoadState")()
+
 	ring.mu.Lock()
 	defer ring.mu.Unlock()
 
-	// Check if the node exists.
-	vFactor, ok := ring.vFactorByNode[identifier]
-	if !ok {
-		return
-	}
+	return ring.loadState(state, nil, nil)
+}
 
-	for idx := 0; idx < vFactor*ring.BaseVFactor; idx++ {
-		ring.removeSlice(ring.Hash(ring.ToSliceName(identifier, idx)))
+// loadState replaces the entire state of the ring with the state, loading each key with its payload, if any, and
+// pinning the pinned keys. ErrInconsistentState is returned if there is a payload for a key absent from the state.
+func (ring *Ring[T]) loadState(state *State, payloads map[string]T, pinnedKeys map[string]string) error {
+	s := &snapshot[T]{
+		VFactorByNode: make(map[string]int),
+		ZoneByNode:    make(map[string]string),
+		NodesBySlice:  make(map[uint64]string, len(state.NodesBySlice)),
+		Keys:          make([]snapshotKey[T], 0, len(state.HashesByKey)),
+		PinnedKeys:    pinnedKeys,
 	}
 
-	// Delete vFactor.
-	delete(ring.vFactorByNode, identifier)
-}
+	for _, slice := range state.SlicesByHash {
+		_, ok := state.NodesBySlice[slice]
+		if !ok {
+			return ErrInconsistentState
+		}
+	}
 
-// UpdateNode attempts to update a node by adding or removing slices based on the new VFactor of that node.
-// If the VFactor is the same as it was previously, nothing will change.
-func (ring *Ring[T]) UpdateNode(node Node) error {
-	ring.mu.Lock()
-	defer ring.mu.Unlock()
+	for key := range payloads {
+		_, ok := state.HashesByKey[key]
+		if !ok {
+			return ErrInconsistentState
+		}
+	}
 
-	vFactor, ok := ring.vFactorByNode[node.Identifier]
-	if !ok {
-		return ErrNodeNotFound
+	slicesByNode := make(map[string]int)
+	for slice, node := range state.NodesBySlice {
+		s.NodesBySlice[slice] = node
+		slicesByNode[node]++
 	}
 
-	if node.VFactor == vFactor {
-		return nil
+	for node, slices := range slicesByNode {
+		s.VFactorByNode[node] = max(1, (slices+ring.BaseVFactor-1)/ring.BaseVFactor)
 	}
 
-	if node.VFactor > vFactor {
-		for idx := vFactor * ring.BaseVFactor; idx < node.VFactor*ring.BaseVFactor; idx++ {
-			slice := ring.Hash(ring.ToSliceName(node.Identifier, idx))
-			err := ring.insertSlice(slice, node.Identifier)
-			if err == ErrSliceAlreadyExists {
-				return ErrSliceHashCollision
+	for key, hash := range state.HashesByKey {
+		if len(state.NodesBySlice) > 0 {
+			_, ok := state.SlicesByHash[hash]
+			if !ok {
+				return ErrInconsistentState
 			}
 		}
-	} else {
-		for idx := node.VFactor * ring.BaseVFactor; idx < vFactor*ring.BaseVFactor; idx++ {
-			slice := ring.Hash(ring.ToSliceName(node.Identifier, idx))
-			ring.removeSlice(slice)
-		}
+
+		s.Keys = append(s.Keys, snapshotKey[T]{
+			Key:     key,
+			Hash:    hash,
+			Payload: payloads[key],
+		})
 	}
 
-	ring.vFactorByNode[node.Identifier] = node.VFactor
+	// Load keys sharing a hash in a stable order, as they all have the same order.
+	sort.Slice(s.Keys, func(i, j int) bool { return s.Keys[i].Key < s.Keys[j].Key })
 
-	return nil
+	return ring.restore(s)
 }
 
-// GetNode attempts to find the node with the provided identifier.
-func (ring *Ring[T]) GetNode(identifier string) (Node, error) {
+// Layout provides the position of every slice on the ring, in ascending order of hash, along with the node owning
+// each slice. Each slice owns the hashes from its own up to the next slice, wrapping around after the last slice.
+func (ring *Ring[T]) Layout() []SlicePosition {
 	ring.mu.RLock()
 	defer ring.mu.RUnlock()
-	vFactor, ok := ring.vFactorByNode[identifier]
-	if !ok {
-		return Node{}, ErrNodeNotFound
+
+	layout := make([]SlicePosition, 0, len(ring.slices))
+	for _, slice := range ring.slices {
+		layout = append(layout, SlicePosition{
+			Hash: slice,
+			Node: ring.nodesBySlice[slice],
+		})
 	}
 
-	return Node{
-		Identifier: identifier,
-		VFactor:    vFactor,
-	}, nil
+	return layout
 }
 
-// ListNodes lists the identifiers of the current nodes of the hash ring.
-func (ring *Ring[T]) ListNodes() []string {
+// TopologyState provides a copy of the ring's nodes and slices.
+func (ring *Ring[T]) TopologyState() *TopologyState {
 	ring.mu.RLock()
 	defer ring.mu.RUnlock()
-	nodes := make([]string, 0, len(ring.vFactorByNode))
 
-	for node := range ring.vFactorByNode {
-		nodes = append(nodes, node)
+	topology := &TopologyState{
+		Slices:        make([]uint64, len(ring.slices)),
+		NodesBySlice:  make(map[uint64]string, len(ring.nodesBySlice)),
+		VFactorByNode: make(map[string]int, len(ring.vFactorByNode)),
+		ZoneByNode:    make(map[string]string, len(ring.zoneByNode)),
 	}
 
-	return nodes
+	copy(topology.Slices, ring.slices)
+	for slice, node := range ring.nodesBySlice {
+		topology.NodesBySlice[slice] = node
+	}
+	for node, vFactor := range ring.vFactorByNode {
+		topology.VFactorByNode[node] = vFactor
+	}
+	for node, zone := range ring.zoneByNode {
+		topology.ZoneByNode[node] = zone
+	}
+
+	return topology
 }
 
-func (ring *Ring[T]) insertSlice(slice uint64, node string) error {
+// TopologyHash provides a fingerprint of the ring's slices and the nodes they belong to, which changes whenever
+// the topology changes but not when keys are emplaced, removed, or moved. It depends only on the topology
+// itself, so rings with the same topology have the same fingerprint however it was built.
+func (ring *Ring[T]) TopologyHash() uint64 {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
 
-	// Check to see if slice already exists.
-	_, ok := ring.nodesBySlice[slice]
-	if ok {
-		return ErrSliceAlreadyExists
+	hash := fnv.New64a()
+	buf := make([]byte, 0, 12)
+	for _, slice := range ring.slices {
+		node := ring.nodesBySlice[slice]
+
+		buf = binary.BigEndian.AppendUint64(buf[:0], slice)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(node)))
+		hash.Write(buf)
+		hash.Write([]byte(node))
 	}
 
-	// Insert new slice into slices and retrieve index.
-	var idx int
-	ring.slices, idx = insertPreserveOrder(ring.slices, slice, findIndex)
+	return hash.Sum64()
+}
 
-	// Add to nodes by slice.
-	ring.nodesBySlice[slice] = node
+// LoadTopology applies the nodes and slices of the topology to a ring which has no nodes yet.
+// Any keys already held in the empty container are moved onto the loaded slices.
+func (ring *Ring[T]) LoadTopology(topology *TopologyState) error {
+	defer ring.timeOp("LoadTopology")()
 
-	// If this is the first slice, attempt to move in keys from the empty container.
-	if len(ring.slices) == 1 {
-		for _, hash := range ring.empty {
-			ring.slicesByHash[hash] = slice
-			for _, key := range ring.keysByHash[hash] {
-				ring.notify(Op[T]{
-					Key:        key.Key,
-					Payload:    ring.contentByKey[key.Key],
-					Node:       ring.nodesBySlice[slice],
-					RingChange: true,
-				})
-			}
-			delete(ring.empty, hash)
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	if len(ring.vFactorByNode) > 0 {
+		return ErrTopologyAlreadyExists
+	}
+
+	// Every slice must be unique and belong to exactly one known node.
+	if len(topology.Slices) != len(topology.NodesBySlice) {
+		return ErrInconsistentState
+	}
+	seen := make(map[uint64]struct{}, len(topology.Slices))
+	for _, slice := range topology.Slices {
+		_, ok := seen[slice]
+		if ok {
+			return ErrInconsistentState
 		}
-	} else { // Otherwise convert the hashes taken from the next slice.
-		nextSlice := ring.slices[findNextIndex(ring.slices, idx)]
+		seen[slice] = struct{}{}
 
-		// Convert ring hashes to new slice starting at the first hash greater than the new slice and ending at the next slice.
-		ring.convertHashes(
-			slice,
-			findIndex(ring.hashes, slice),
-			findIndex(ring.hashes, nextSlice),
-			nextSlice < slice,
-		)
+		node, ok := topology.NodesBySlice[slice]
+		if !ok {
+			return ErrInconsistentState
+		}
+
+		_, ok = topology.VFactorByNode[node]
+		if !ok {
+			return ErrInconsistentState
+		}
+	}
+
+	nodes := make([]string, 0, len(topology.VFactorByNode))
+	for node, vFactor := range topology.VFactorByNode {
+		if vFactor < 1 {
+			return ErrInvalidVFactor
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	for _, node := range nodes {
+		ring.emitNodeEvent(NodeEvent{
+			Node: Node{
+				Identifier: node,
+				VFactor:    topology.VFactorByNode[node],
+				Zone:       topology.ZoneByNode[node],
+			},
+		})
+	}
+
+	// Insert every slice at once, each as its own group so that slice events follow the order of the topology.
+	owners := make([]string, len(topology.Slices))
+	slicesByOwner := make([][]uint64, len(topology.Slices))
+	for idx, slice := range topology.Slices {
+		owners[idx] = topology.NodesBySlice[slice]
+		slicesByOwner[idx] = []uint64{slice}
+	}
+
+	_, err := ring.collectMoves(func() error {
+		ring.startConvert(nil, topology.Slices)
+		return ring.insertNodeSlices(owners, slicesByOwner)
+	})
+	if err != nil {
+		return err
+	}
+
+	for node, vFactor := range topology.VFactorByNode {
+		ring.vFactorByNode[node] = vFactor
+	}
+	for node, zone := range topology.ZoneByNode {
+		_, ok := topology.VFactorByNode[node]
+		if ok && zone != "" {
+			ring.zoneByNode[node] = zone
+		}
 	}
 
 	return nil
 }
 
-func (ring *Ring[T]) removeSlice(slice uint64) {
+// CreateNode attempts to add a new node to the hash ring, including all of that nodes associated slices.
+// The nodes VFactor determines how many slices will be associated with the particular node, and must be
+// at least one, since a node without slices would never own any keys. If any of the slices would collide
+// with an existing slice or with each other, ErrSliceHashCollision is returned and the ring is unchanged.
+// If creating the node would move more than the MaxMovementFraction of all keys, ErrMovementExceeded is returned.
+func (ring *Ring[T]) CreateNode(node Node) error {
+	defer ring.timeOp("CreateNode")()
 
-	// Noop if slice doesn't exist.
-	_, ok := ring.nodesBySlice[slice]
+	if node.VFactor < 1 {
+		return ErrInvalidVFactor
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	// Check to see if node already exists.
+	_, ok := ring.vFactorByNode[node.Identifier]
+	if ok {
+		return ErrNodeAlreadyExists
+	}
+
+	// Compute all virtual slices, so that a collision leaves the ring unchanged.
+	slices, err := ring.newSlices(node.Identifier, 0, node.VFactor*ring.BaseVFactor)
+	if err != nil {
+		return err
+	}
+
+	err = ring.checkMovement(func(clone *Ring[T]) error {
+		return clone.insertSlices(slices, node.Identifier)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Save vfactor and zone.
+	ring.vFactorByNode[node.Identifier] = node.VFactor
+	if node.Zone != "" {
+		ring.zoneByNode[node.Identifier] = node.Zone
+	}
+	ring.emitNodeEvent(NodeEvent{Node: node})
+
+	// Insert all virtual slices.
+	_, err = ring.collectMoves(func() error {
+		ring.startConvert(nil, slices)
+		return ring.insertSlices(slices, node.Identifier)
+	})
+
+	return err
+}
+
+// CreateNodes attempts to add every provided node to the ring at once, as CreateNode would for each.
+// Every node is validated before any is created, so if any node is invalid, already exists, is provided twice,
+// or has a slice colliding with an existing slice or a slice of another provided node, the ring is unchanged.
+// Only the net movement of each key is notified once every node has been created. If creating the nodes would
+// move more than the MaxMovementFraction of all keys, ErrMovementExceeded is returned and the ring is unchanged.
+func (ring *Ring[T]) CreateNodes(nodes []Node) error {
+	defer ring.timeOp("CreateNodes")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	// Compute the virtual slices of every node, so that any collision leaves the ring unchanged.
+	identifiers := make([]string, len(nodes))
+	slicesByNode := make([][]uint64, len(nodes))
+	var added []uint64
+	seenNodes := make(map[string]struct{}, len(nodes))
+	seenSlices := make(map[uint64]struct{})
+	for idx, node := range nodes {
+		if node.VFactor < 1 {
+			return ErrInvalidVFactor
+		}
+
+		_, exists := ring.vFactorByNode[node.Identifier]
+		_, duplicate := seenNodes[node.Identifier]
+		if exists || duplicate {
+			return ErrNodeAlreadyExists
+		}
+		seenNodes[node.Identifier] = struct{}{}
+
+		slices, err := ring.newSlices(node.Identifier, 0, node.VFactor*ring.BaseVFactor)
+		if err != nil {
+			return err
+		}
+
+		for _, slice := range slices {
+			_, ok := seenSlices[slice]
+			if ok {
+				return ErrSliceHashCollision
+			}
+			seenSlices[slice] = struct{}{}
+		}
+		identifiers[idx] = node.Identifier
+		slicesByNode[idx] = slices
+		added = append(added, slices...)
+	}
+
+	err := ring.checkMovement(func(clone *Ring[T]) error {
+		return clone.insertNodeSlices(identifiers, slicesByNode)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		ring.vFactorByNode[node.Identifier] = node.VFactor
+		if node.Zone != "" {
+			ring.zoneByNode[node.Identifier] = node.Zone
+		}
+		ring.emitNodeEvent(NodeEvent{Node: node})
+	}
+
+	_, err = ring.collectMoves(func() error {
+		ring.startConvert(nil, added)
+		return ring.insertNodeSlices(identifiers, slicesByNode)
+	})
+
+	return err
+}
+
+// newSlices computes the slices of the node with indexes from the start up to the end, returning
+// ErrSliceHashCollision if any slice collides with an existing slice or with another of the slices.
+func (ring *Ring[T]) newSlices(identifier string, start, end int) ([]uint64, error) {
+	slices := make([]uint64, 0, end-start)
+	seen := make(map[uint64]struct{}, end-start)
+	for idx := start; idx < end; idx++ {
+		slice := ring.HashSlice(identifier, idx)
+
+		_, exists := ring.nodesBySlice[slice]
+		_, duplicate := seen[slice]
+		if exists || duplicate {
+			return nil, ErrSliceHashCollision
+		}
+
+		seen[slice] = struct{}{}
+		slices = append(slices, slice)
+	}
+
+	return slices, nil
+}
+
+// nodeSlices computes the slices of the node with indexes from the start up to the end.
+func (ring *Ring[T]) nodeSlices(identifier string, start, end int) []uint64 {
+	slices := make([]uint64, 0, end-start)
+	for idx := start; idx < end; idx++ {
+		slices = append(slices, ring.HashSlice(identifier, idx))
+	}
+
+	return slices
+}
+
+// DeleteNode attempts to remove a node from the hash ring given the node's identifier.
+// It is a noop if no node with the given identifier exists. It is never limited by MaxMovementFraction;
+// DeleteNodeErr and TryDeleteNode delete a node only within the limit.
+func (ring *Ring[T]) DeleteNode(identifier string) {
+	defer ring.timeOp("DeleteNode")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	ring.deleteNode(identifier)
+}
+
+// DeleteNodeErr attempts to remove a node from the hash ring as DeleteNode would, returning ErrNodeNotFound
+// if no node with the given identifier exists. Unlike DeleteNode, if deleting the node would move more than the
+// MaxMovementFraction of all keys, ErrMovementExceeded is returned and the ring is unchanged.
+func (ring *Ring[T]) DeleteNodeErr(identifier string) error {
+	defer ring.timeOp("DeleteNodeErr")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	return ring.tryDeleteNode(identifier)
+}
+
+// TryDeleteNode attempts to remove a node from the hash ring as DeleteNode would, unless deleting it would move
+// more than the MaxMovementFraction of all keys, in which case ErrMovementExceeded is returned and the ring is
+// unchanged. ErrNodeNotFound is returned if no node with the given identifier exists. It behaves exactly as
+// DeleteNodeErr does.
+func (ring *Ring[T]) TryDeleteNode(identifier string) error {
+	defer ring.timeOp("TryDeleteNode")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	return ring.tryDeleteNode(identifier)
+}
+
+// tryDeleteNode removes the existing node unless doing so would move more than the MaxMovementFraction of all keys.
+func (ring *Ring[T]) tryDeleteNode(identifier string) error {
+	_, ok := ring.vFactorByNode[identifier]
+	if !ok {
+		return ErrNodeNotFound
+	}
+
+	if ring.MaxMovementFraction > 0 && ring.exceedsMaxMovement(ring.simulateDeleteNode(identifier)) {
+		return ErrMovementExceeded
+	}
+
+	ring.deleteNode(identifier)
+
+	return nil
+}
+
+// deleteNode removes the node with the given identifier, if it exists.
+func (ring *Ring[T]) deleteNode(identifier string) {
+	// Check if the node exists.
+	vFactor, ok := ring.vFactorByNode[identifier]
 	if !ok {
 		return
 	}
 
-	// Find the current index of the slice.
-	sliceIdx := findIndex(ring.slices, slice)
+	ring.emitNodeEvent(NodeEvent{
+		Node: Node{
+			Identifier: identifier,
+			VFactor:    vFactor,
+			Zone:       ring.zoneByNode[identifier],
+		},
+		Removed: true,
+	})
 
-	// If this is the final slice in the ring, move hashes into the empty container.
-	if len(ring.slices) == 1 {
-		for _, hash := range ring.hashes {
-			for _, key := range ring.keysByHash[hash] {
-				ring.notify(Op[T]{
-					Key:        key.Key,
-					Payload:    ring.contentByKey[key.Key],
-					Node:       ring.nodesBySlice[slice],
-					Removed:    true,
-					RingChange: true,
-				})
+	ring.unpinNode(identifier)
+	slices := ring.nodeSlices(identifier, 0, vFactor*ring.BaseVFactor)
+	_, _ = ring.collectMoves(func() error {
+		ring.startConvert(slices, nil)
+		ring.removeSlices(slices)
+		return nil
+	})
+
+	// Delete vFactor and zone.
+	delete(ring.vFactorByNode, identifier)
+	delete(ring.zoneByNode, identifier)
+}
+
+// DrainNode removes a node from the ring gradually, removing one of its slices at a time and releasing the lock in
+// between, so that its keys move to other nodes in small steps which other callers can observe and interleave with.
+// The node remains in the ring while it is drained, owning progressively fewer slices, and is deleted once it has none.
+// If deleting the node would move more than the MaxMovementFraction of all keys, ErrMovementExceeded is returned
+// before any slice is removed.
+func (ring *Ring[T]) DrainNode(identifier string) error {
+	defer ring.timeOp("DrainNode")()
+
+	ring.mu.Lock()
+	_, ok := ring.vFactorByNode[identifier]
+	if !ok {
+		ring.mu.Unlock()
+		return ErrNodeNotFound
+	}
+
+	// The whole drain moves the same keys as deleting the node at once.
+	if ring.MaxMovementFraction > 0 && ring.exceedsMaxMovement(ring.simulateDeleteNode(identifier)) {
+		ring.mu.Unlock()
+		return ErrMovementExceeded
+	}
+
+	ring.mu.Unlock()
+
+	for ring.drainSlice(identifier) {
+	}
+
+	return nil
+}
+
+// drainSlice removes the last remaining slice of the node, deleting the node if it has no slices left.
+// It reports whether the node still remains in the ring.
+func (ring *Ring[T]) drainSlice(identifier string) bool {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	// The node may have been deleted while it was being drained.
+	vFactor, ok := ring.vFactorByNode[identifier]
+	if !ok {
+		return false
+	}
+
+	for idx := vFactor*ring.BaseVFactor - 1; idx >= 0; idx-- {
+		slice := ring.HashSlice(identifier, idx)
+		if ring.nodesBySlice[slice] == identifier {
+			_, _ = ring.collectMoves(func() error {
+				ring.startConvert([]uint64{slice}, nil)
+				ring.removeSlices([]uint64{slice})
+				return nil
+			})
+			return true
+		}
+	}
+
+	ring.emitNodeEvent(NodeEvent{
+		Node: Node{
+			Identifier: identifier,
+			VFactor:    vFactor,
+			Zone:       ring.zoneByNode[identifier],
+		},
+		Removed: true,
+	})
+
+	ring.unpinNode(identifier)
+	delete(ring.vFactorByNode, identifier)
+	delete(ring.zoneByNode, identifier)
+
+	return false
+}
+
+// UpdateNode attempts to update a node by adding or removing slices based on the new VFactor of that node.
+// If the VFactor is the same as it was previously, nothing will change. The zone of a node is fixed when it is
+// created, so the Zone of the provided node is ignored. The VFactor must be at least one; a node is removed
+// with DeleteNode instead. If any new slice would collide with an existing slice, ErrSliceHashCollision is
+// returned and the node is unchanged, as is ErrMovementExceeded if the update would move more than the
+// MaxMovementFraction of all keys.
+func (ring *Ring[T]) UpdateNode(node Node) error {
+	defer ring.timeOp("UpdateNode")()
+
+	if node.VFactor < 1 {
+		return ErrInvalidVFactor
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	_, ok := ring.vFactorByNode[node.Identifier]
+	if !ok {
+		return ErrNodeNotFound
+	}
+
+	err := ring.checkMovement(func(clone *Ring[T]) error {
+		return clone.resizeNode(node.Identifier, node.VFactor)
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = ring.collectMoves(func() error {
+		return ring.resizeNode(node.Identifier, node.VFactor)
+	})
+
+	return err
+}
+
+// ResizeNodes applies new VFactors to multiple nodes at once, given the new VFactor of each node by identifier.
+// Every node must exist, every VFactor must be at least one, and no new slice may collide with an existing slice
+// or with another new slice, and the resize must not move more than the MaxMovementFraction of all keys, otherwise
+// no node is resized and nothing is notified. Only the net movement of each key is notified once all nodes have
+// been resized, and the number of keys which changed owners is returned.
+func (ring *Ring[T]) ResizeNodes(targets map[string]int) (moved int, err error) {
+	defer ring.timeOp("ResizeNodes")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	// Validate every node before resizing any of them.
+	identifiers := make([]string, 0, len(targets))
+	for identifier, vFactor := range targets {
+		if vFactor < 1 {
+			return 0, ErrInvalidVFactor
+		}
+
+		_, ok := ring.vFactorByNode[identifier]
+		if !ok {
+			return 0, ErrNodeNotFound
+		}
+		identifiers = append(identifiers, identifier)
+	}
+
+	// Resize in a stable order so that events are emitted deterministically.
+	sort.Strings(identifiers)
+
+	err = ring.checkMovement(func(clone *Ring[T]) error {
+		return clone.resizeNodes(identifiers, targets)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return ring.collectMoves(func() error {
+		return ring.resizeNodes(identifiers, targets)
+	})
+}
+
+func (ring *Ring[T]) resizeNode(identifier string, vFactor int) error {
+	return ring.resizeNodes([]string{identifier}, map[string]int{identifier: vFactor})
+}
+
+// resizeNodes applies the new VFactors to the nodes, removing the slices of every shrinking node and then inserting
+// the slices of every growing node at once, so that each hash is converted at most once by each. The new slices of
+// every node are computed first, so that any collision leaves every node unchanged. It must be called while moves
+// are being collected.
+func (ring *Ring[T]) resizeNodes(identifiers []string, targets map[string]int) error {
+	var removed, added []uint64
+	var growing []string
+	var slicesByNode [][]uint64
+	seen := make(map[uint64]struct{})
+	for _, identifier := range identifiers {
+		prevVFactor, vFactor := ring.vFactorByNode[identifier], targets[identifier]
+		if vFactor < prevVFactor {
+			removed = append(removed, ring.nodeSlices(identifier, vFactor*ring.BaseVFactor, prevVFactor*ring.BaseVFactor)...)
+			continue
+		}
+
+		if vFactor == prevVFactor {
+			continue
+		}
+
+		slices, err := ring.newSlices(identifier, prevVFactor*ring.BaseVFactor, vFactor*ring.BaseVFactor)
+		if err != nil {
+			return err
+		}
+
+		for _, slice := range slices {
+			_, ok := seen[slice]
+			if ok {
+				return ErrSliceHashCollision
 			}
-			ring.empty[hash] = hash
+			seen[slice] = struct{}{}
 		}
-	} else {
-		// Find the previous slice.
-		prevSlice := ring.slices[findPrevIndex(ring.slices, sliceIdx)]
+		growing = append(growing, identifier)
+		slicesByNode = append(slicesByNode, slices)
+		added = append(added, slices...)
+	}
 
-		nextSlice := ring.slices[findNextIndex(ring.slices, sliceIdx)]
+	for _, identifier := range identifiers {
+		if targets[identifier] == ring.vFactorByNode[identifier] {
+			continue
+		}
 
-		// Convert all of this slice's hashes to belong to the previous slice.
-		ring.convertHashes(
-			prevSlice,
-			findIndex(ring.hashes, slice),
-			findIndex(ring.hashes, nextSlice),
-			nextSlice < slice,
-		)
+		ring.emitNodeEvent(NodeEvent{
+			Node: Node{
+				Identifier: identifier,
+				VFactor:    targets[identifier],
+				Zone:       ring.zoneByNode[identifier],
+			},
+			Updated: true,
+		})
+	}
+
+	ring.startConvert(removed, added)
+	ring.removeSlices(removed)
+	err := ring.insertNodeSlices(growing, slicesByNode)
+	if err != nil {
+		return err
+	}
+
+	for _, identifier := range identifiers {
+		ring.vFactorByNode[identifier] = targets[identifier]
+	}
+
+	return nil
+}
+
+// HasNode reports whether a node with the provided identifier is in the ring.
+func (ring *Ring[T]) HasNode(identifier string) bool {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	_, ok := ring.vFactorByNode[identifier]
+	return ok
+}
+
+// GetNode attempts to find the node with the provided identifier.
+func (ring *Ring[T]) GetNode(identifier string) (Node, error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+	vFactor, ok := ring.vFactorByNode[identifier]
+	if !ok {
+		return Node{}, ErrNodeNotFound
+	}
+
+	return Node{
+		Identifier: identifier,
+		VFactor:    vFactor,
+		Zone:       ring.zoneByNode[identifier],
+	}, nil
+}
+
+// ListNodes lists the identifiers of the current nodes of the hash ring.
+func (ring *Ring[T]) ListNodes() []string {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+	nodes := make([]string, 0, len(ring.vFactorByNode))
+
+	for node := range ring.vFactorByNode {
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// SortedListNodes lists the identifiers of the current nodes of the hash ring in ascending order,
+// for callers which need the same order on every call.
+func (ring *Ring[T]) SortedListNodes() []string {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+	nodes := make([]string, 0, len(ring.vFactorByNode))
+
+	for node := range ring.vFactorByNode {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	return nodes
+}
+
+// ListNodesDetailed lists the current nodes of the hash ring, including their VFactors and zones.
+// Like ListNodes, the nodes are listed in no particular order.
+func (ring *Ring[T]) ListNodesDetailed() []Node {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+	nodes := make([]Node, 0, len(ring.vFactorByNode))
+
+	for node, vFactor := range ring.vFactorByNode {
+		nodes = append(nodes, Node{
+			Identifier: node,
+			VFactor:    vFactor,
+			Zone:       ring.zoneByNode[node],
+		})
+	}
+
+	return nodes
+}
+
+// NodesInZone lists the identifiers of the nodes in the zone, sorted. Nodes without a zone belong to no zone,
+// so none are listed for the empty zone.
+func (ring *Ring[T]) NodesInZone(zone string) []string {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	return ring.nodesInZone(zone, "")
+}
+
+// Colocated lists the identifiers of every other node in the same zone as the node, sorted.
+// A node without a zone is colocated with no other node.
+func (ring *Ring[T]) Colocated(identifier string) ([]string, error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	_, ok := ring.vFactorByNode[identifier]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+
+	return ring.nodesInZone(ring.zoneByNode[identifier], identifier), nil
+}
+
+func (ring *Ring[T]) nodesInZone(zone string, exclude string) []string {
+	if zone == "" {
+		return nil
+	}
+
+	var nodes []string
+	for node, nodeZone := range ring.zoneByNode {
+		if nodeZone == zone && node != exclude {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Strings(nodes)
+
+	return nodes
+}
+
+// KeyCount provides the number of keys in the ring, including any held in the empty container.
+func (ring *Ring[T]) KeyCount() int {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	return len(ring.hashesByKey)
+}
+
+// NodeCount provides the number of nodes in the ring.
+func (ring *Ring[T]) NodeCount() int {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	return len(ring.vFactorByNode)
+}
+
+// SliceCount provides the number of slices in the ring, across every node.
+func (ring *Ring[T]) SliceCount() int {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	return len(ring.slices)
+}
+
+// ListSlicesForNode lists the hashes of the slices currently belonging to the node, in ascending order.
+// The list is empty if the node does not exist.
+func (ring *Ring[T]) ListSlicesForNode(identifier string) []uint64 {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	slices := make([]uint64, 0)
+	for _, slice := range ring.slices {
+		if ring.nodesBySlice[slice] == identifier {
+			slices = append(slices, slice)
+		}
+	}
+
+	return slices
+}
+
+// IsEmpty reports whether the ring currently has no slices, in which case any emplaced keys
+// are held in the empty container until a node is created.
+func (ring *Ring[T]) IsEmpty() bool {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+	return len(ring.slices) == 0
+}
+
+// DetectSliceCollisions recomputes the slices each node intended to occupy and reports, by slice hash, the
+// identifiers of the nodes which could not occupy them because the slice was already taken, either by
+// another node or by another of the same node's slices. Such nodes have fewer effective slices than their
+// VFactor implies.
+func (ring *Ring[T]) DetectSliceCollisions() map[uint64][]string {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	identifiers := make([]string, 0, len(ring.vFactorByNode))
+	for identifier := range ring.vFactorByNode {
+		identifiers = append(identifiers, identifier)
+	}
+	sort.Strings(identifiers)
+
+	collisions := make(map[uint64][]string)
+	for _, identifier := range identifiers {
+		intended := make(map[uint64]struct{})
+		for idx := 0; idx < ring.vFactorByNode[identifier]*ring.BaseVFactor; idx++ {
+			slice := ring.HashSlice(identifier, idx)
+
+			// Slices never inserted are not collisions.
+			node, ok := ring.nodesBySlice[slice]
+			if !ok {
+				continue
+			}
+
+			_, duplicate := intended[slice]
+			if node != identifier || duplicate {
+				collisions[slice] = append(collisions[slice], identifier)
+			}
+			intended[slice] = struct{}{}
+		}
+	}
+
+	return collisions
+}
+
+// NodeShare computes the fraction of the ring's hash space owned by the node with the provided
+// identifier, which is the theoretical share of keys that node will receive.
+func (ring *Ring[T]) NodeShare(identifier string) (float64, error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	_, ok := ring.vFactorByNode[identifier]
+	if !ok {
+		return 0, ErrNodeNotFound
+	}
+
+	var share float64
+	for idx, slice := range ring.slices {
+		if ring.nodesBySlice[slice] != identifier {
+			continue
+		}
+
+		span, whole := ring.arcLength(idx)
+		if whole {
+			return 1, nil
+		}
+		share += float64(span)
+	}
+
+	return share / math.Exp2(64), nil
+}
+
+// ArcCoverage sums, for each node, the span of the ring's hash space owned by its slices, which is the clockwise
+// distance from each slice to the next. Unlike a count of keys, this is the theoretical load of each node
+// independent of the keys currently in the ring. The spans of all nodes together cover the entire hash space of
+// 2^64 hashes, which cannot be represented, so a node owning the entire ring is reported with math.MaxUint64.
+func (ring *Ring[T]) ArcCoverage() map[string]uint64 {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	coverage := make(map[string]uint64, len(ring.vFactorByNode))
+	if len(ring.vFactorByNode) == 1 && len(ring.slices) > 0 {
+		coverage[ring.nodesBySlice[ring.slices[0]]] = math.MaxUint64
+		return coverage
+	}
+
+	for idx, slice := range ring.slices {
+		span, _ := ring.arcLength(idx)
+		coverage[ring.nodesBySlice[slice]] += span
+	}
+
+	return coverage
+}
+
+// KeyPositionsForNode counts the distinct ring positions holding keys owned by the node with the provided identifier,
+// along with the total number of keys it owns. Many keys sharing few positions will move together when rebalancing.
+func (ring *Ring[T]) KeyPositionsForNode(identifier string) (positions int, keys int, err error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	_, ok := ring.vFactorByNode[identifier]
+	if !ok {
+		return 0, 0, ErrNodeNotFound
+	}
+
+	// Keys in the empty container belong to no node, unless they are pinned.
+	for _, hash := range ring.hashes {
+		owned := 0
+		for _, key := range ring.keysByHash[hash] {
+			if ring.ownerOfKey(key.Key, hash) == (owner{node: identifier}) {
+				owned++
+			}
+		}
+		if owned == 0 {
+			continue
+		}
+
+		positions++
+		keys += owned
+	}
+
+	return positions, keys, nil
+}
+
+// NodeSnapshot provides an add op for every key currently owned by the node with the provided identifier,
+// so that a recovering node can rebuild its own key set. Ops are ordered by hash and then by key order.
+func (ring *Ring[T]) NodeSnapshot(identifier string) ([]Op[T], error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	_, ok := ring.vFactorByNode[identifier]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+
+	ops := []Op[T]{}
+	for _, hash := range ring.hashes {
+		for _, key := range ring.keysByHash[hash] {
+			if ring.ownerOfKey(key.Key, hash) != (owner{node: identifier}) {
+				continue
+			}
+
+			ops = append(ops, Op[T]{
+				Key:     key.Key,
+				Node:    identifier,
+				Payload: ring.contentByKey[key.Key],
+			})
+		}
+	}
+
+	return ops, nil
+}
+
+// ColdSlices lists, in ascending order, the slices whose arcs of the ring currently contain no keys.
+// Many cold slices may indicate that the VFactors of the ring's nodes are higher than necessary.
+func (ring *Ring[T]) ColdSlices() []uint64 {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	warm := make(map[uint64]struct{}, len(ring.slices))
+	if len(ring.slices) > 0 {
+		for _, hash := range ring.hashes {
+			warm[ring.slicesByHash[hash]] = struct{}{}
+		}
+	}
+
+	cold := []uint64{}
+	for _, slice := range ring.slices {
+		_, ok := warm[slice]
+		if !ok {
+			cold = append(cold, slice)
+		}
+	}
+
+	return cold
+}
+
+// OrderingReport provides, for each hash shared by multiple keys, the keys at that hash in the order in which
+// they are notified, as determined by their orders.
+func (ring *Ring[T]) OrderingReport() map[uint64][]string {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	report := make(map[uint64][]string)
+	for hash, keys := range ring.keysByHash {
+		if len(keys) < 2 {
+			continue
+		}
+
+		names := make([]string, len(keys))
+		for idx, key := range keys {
+			names[idx] = key.Key
+		}
+		report[hash] = names
+	}
+
+	return report
+}
+
+// arcLength returns the span of the hash space governed by the slice at the provided index, which
+// is the clockwise distance to the next slice. A lone slice governs the entire ring, whose span
+// cannot be represented in a uint64, so this case is reported separately.
+func (ring *Ring[T]) arcLength(idx int) (span uint64, whole bool) {
+	if len(ring.slices) == 1 {
+		return 0, true
+	}
+
+	// Unsigned subtraction wraps around the top of the ring for the final slice.
+	return ring.slices[findNextIndex(ring.slices, idx)] - ring.slices[idx], false
+}
+
+func (ring *Ring[T]) insertSlice(slice uint64, node string) error {
+
+	// Check to see if slice already exists.
+	_, ok := ring.nodesBySlice[slice]
+	if ok {
+		return ErrSliceAlreadyExists
+	}
+
+	// Insert new slice into slices and retrieve index.
+	var idx int
+	ring.slices, idx = insertPreserveOrder(ring.slices, slice, findIndex)
+
+	// Add to nodes by slice.
+	ring.nodesBySlice[slice] = node
+	ring.recordViewChange(slice, node, false)
+	ring.emit(SliceEvent{
+		Slice: slice,
+		Node:  node,
+	})
+
+	// If this is the first slice, attempt to move in keys from the empty container.
+	if len(ring.slices) == 1 {
+		for _, hash := range ring.empty {
+			ring.slicesByHash[hash] = slice
+			delete(ring.empty, hash)
+			if ring.moves != nil {
+				ring.recordMove(hash, owner{parked: true})
+				continue
+			}
+
+			for _, key := range ring.unpinnedKeys(hash) {
+				ring.Metrics.IncMovement(ring.nodesBySlice[slice])
+				ring.notify(Op[T]{
+					Key:        key.Key,
+					Payload:    ring.contentByKey[key.Key],
+					Node:       ring.nodesBySlice[slice],
+					RingChange: true,
+				})
+			}
+		}
+	} else { // Otherwise convert the hashes taken from the next slice.
+		nextSlice := ring.slices[findNextIndex(ring.slices, idx)]
+
+		// Convert ring hashes to new slice starting at the first hash greater than the new slice and ending at the next slice.
+		ring.convertHashes(
+			slice,
+			findIndex(ring.hashes, slice),
+			findIndex(ring.hashes, nextSlice),
+			nextSlice < slice,
+		)
+	}
+
+	return nil
+}
+
+// insertSlices inserts every slice for the node as insertSlice would, but merges them into the slices at once,
+// so that inserting many slices costs a single pass over the ring rather than one per slice. It must be called
+// while moves are being collected, as hashes may be converted through several new slices before settling.
+func (ring *Ring[T]) insertSlices(slices []uint64, node string) error {
+	return ring.insertNodeSlices([]string{node}, [][]uint64{slices})
+}
+
+// insertNodeSlices inserts the slices of every node at once as insertSlices would, the slices of each node
+// corresponding to the node by index, so that each hash is converted at most once.
+func (ring *Ring[T]) insertNodeSlices(nodes []string, slicesByNode [][]uint64) error {
+	var added []uint64
+	for _, slices := range slicesByNode {
+		for _, slice := range slices {
+			_, ok := ring.nodesBySlice[slice]
+			if ok {
+				return ErrSliceAlreadyExists
+			}
+		}
+		added = append(added, slices...)
+	}
+
+	sort.Slice(added, func(i, j int) bool { return added[i] < added[j] })
+	ring.slices = mergeSorted(ring.slices, added)
+
+	for idx, node := range nodes {
+		for _, slice := range slicesByNode[idx] {
+			ring.nodesBySlice[slice] = node
+			ring.recordViewChange(slice, node, false)
+			ring.emit(SliceEvent{
+				Slice: slice,
+				Node:  node,
+			})
+		}
+	}
+
+	// If these are the first slices, the keys of the empty container are taken by the new slices below.
+	if len(ring.slices) == len(added) {
+		for _, hash := range ring.empty {
+			ring.recordMove(hash, owner{parked: true})
+			delete(ring.empty, hash)
+		}
+	}
+
+	// Convert the hashes taken by each new slice, up to the slice following it.
+	for _, slice := range added {
+		nextSlice := ring.slices[findNextIndex(ring.slices, findIndex(ring.slices, slice))]
+		ring.convertHashes(
+			slice,
+			findIndex(ring.hashes, slice),
+			findIndex(ring.hashes, nextSlice),
+			nextSlice <= slice,
+		)
+	}
+
+	return nil
+}
+
+func (ring *Ring[T]) removeSlice(slice uint64) {
+
+	// Noop if slice doesn't exist.
+	node, ok := ring.nodesBySlice[slice]
+	if !ok {
+		return
+	}
+
+	ring.emit(SliceEvent{
+		Slice:   slice,
+		Node:    node,
+		Removed: true,
+	})
+
+	// Find the current index of the slice.
+	sliceIdx := findIndex(ring.slices, slice)
+
+	// If this is the final slice in the ring, move hashes into the empty container.
+	if len(ring.slices) == 1 {
+		for _, hash := range ring.hashes {
+			ring.empty[hash] = hash
+			if ring.moves != nil {
+				ring.recordMove(hash, owner{node: ring.nodesBySlice[slice]})
+				continue
+			}
+
+			for _, key := range ring.unpinnedKeys(hash) {
+				ring.notify(Op[T]{
+					Key:        key.Key,
+					Payload:    ring.contentByKey[key.Key],
+					Node:       ring.nodesBySlice[slice],
+					Removed:    true,
+					RingChange: true,
+				})
+			}
+		}
+	} else {
+		// Find the previous slice.
+		prevSlice := ring.slices[findPrevIndex(ring.slices, sliceIdx)]
+
+		nextSlice := ring.slices[findNextIndex(ring.slices, sliceIdx)]
+
+		// Convert all of this slice's hashes to belong to the previous slice.
+		ring.convertHashes(
+			prevSlice,
+			findIndex(ring.hashes, slice),
+			findIndex(ring.hashes, nextSlice),
+			nextSlice < slice,
+		)
+	}
+
+	// Remove the slice from the slices array.
+	ring.slices, _ = removeIndex(ring.slices, sliceIdx)
+
+	// Delete from nodes by slice map.
+	delete(ring.nodesBySlice, slice)
+	ring.recordViewChange(slice, node, true)
+}
+
+// removeSlices removes every slice as removeSlice would, but in a single pass over the ring, converting the hashes
+// of each removed slice straight to the closest remaining slice before it, so that each hash is converted at most
+// once. It must be called while moves are being collected. Slices which do not exist are ignored.
+func (ring *Ring[T]) removeSlices(slices []uint64) {
+	removed := make(map[uint64]struct{}, len(slices))
+	for _, slice := range slices {
+		node, ok := ring.nodesBySlice[slice]
+		if !ok {
+			continue
+		}
+
+		removed[slice] = struct{}{}
+		ring.emit(SliceEvent{
+			Slice:   slice,
+			Node:    node,
+			Removed: true,
+		})
+	}
+
+	if len(removed) == 0 {
+		return
+	}
+
+	remaining := make([]uint64, 0, len(ring.slices)-len(removed))
+	for _, slice := range ring.slices {
+		_, ok := removed[slice]
+		if !ok {
+			remaining = append(remaining, slice)
+		}
+	}
+
+	// If no slice remains, move every hash into the empty container.
+	if len(remaining) == 0 {
+		for _, hash := range ring.hashes {
+			ring.recordMove(hash, ring.ownerOfHash(hash))
+			ring.empty[hash] = hash
+		}
+	} else {
+		for idx, slice := range ring.slices {
+			_, ok := removed[slice]
+			if !ok {
+				continue
+			}
+
+			// Convert the hashes of the slice to the closest remaining slice before it.
+			prevSlice := remaining[findPrevIndex(remaining, findIndex(remaining, slice))]
+			nextSlice := ring.slices[findNextIndex(ring.slices, idx)]
+			ring.convertHashes(
+				prevSlice,
+				findIndex(ring.hashes, slice),
+				findIndex(ring.hashes, nextSlice),
+				nextSlice < slice,
+			)
+		}
+	}
+
+	ring.slices = remaining
+	for slice := range removed {
+		ring.recordViewChange(slice, ring.nodesBySlice[slice], true)
+		delete(ring.nodesBySlice, slice)
+	}
+}
+
+func (ring *Ring[T]) convertHashes(
+	slice uint64,
+	hashStartIndex int,
+	hashEndIndex int,
+	circle bool,
+) {
+
+	// If start and end are equal, convert the entire ring.
+	if circle && hashStartIndex == hashEndIndex ||
+		hashStartIndex == 0 && hashEndIndex == len(ring.hashes) {
+		if !ring.converting {
+			ring.convertDone = 0
+			ring.convertTotal = len(ring.hashes)
+		}
+
+		for _, hash := range ring.hashes {
+			ring.convertHash(slice, hash)
+			ring.reportConvertProgress()
+		}
+		return
+	}
+
+	if hashStartIndex == len(ring.hashes) {
+		hashStartIndex = 0
+	}
+
+	if hashEndIndex == len(ring.hashes) {
+		hashEndIndex = 0
+	}
+
+	// Count the hashes in the range, which may wrap around the end of the ring, unless the progress of the whole
+	// topology change is being reported.
+	if !ring.converting && len(ring.hashes) > 0 {
+		ring.convertDone = 0
+		ring.convertTotal = (hashEndIndex - hashStartIndex + len(ring.hashes)) % len(ring.hashes)
+	}
+
+	// Otherwise convert only the specified range.
+	for {
+		if hashStartIndex == len(ring.hashes) {
+			hashStartIndex = 0
+		}
+
+		if hashStartIndex == hashEndIndex {
+			return
+		}
+
+		ring.convertHash(slice, ring.hashes[hashStartIndex])
+		ring.reportConvertProgress()
+
+		hashStartIndex++
+	}
+}
+
+// timeOp starts timing the named operation, returning a func which reports the elapsed time to
+// OnOpTiming. It is deferred before the ring is locked so the callback runs after it is unlocked.
+func (ring *Ring[T]) timeOp(op string) func() {
+	if ring.OnOpTiming == nil {
+		return func() {}
+	}
+
+	start := ring.Clock.Now()
+	return func() {
+		ring.OnOpTiming(op, ring.Clock.Now().Sub(start))
+	}
+}
+
+// reportConvertProgress counts another hash reassigned, invoking the progress callback, if any, every
+// convertProgressInterval hashes and once the final hash is reassigned.
+func (ring *Ring[T]) reportConvertProgress() {
+	ring.convertDone++
+	if ring.OnConvertProgress == nil {
+		return
+	}
+
+	if ring.convertDone%convertProgressInterval == 0 || ring.convertDone == ring.convertTotal {
+		ring.OnConvertProgress(ring.convertDone, ring.convertTotal)
+	}
+}
+
+// startConvert begins reporting the progress of a topology change which removes the slices and then adds the
+// slices, counting every hash the change will reassign up front: the hashes of each removed slice, unless no slice
+// remains, followed by the hashes each added slice takes once the change completes. Each hash must be reassigned
+// at most once per removal or addition, as removeSlices and insertNodeSlices do. Progress is reported until moves
+// stop being collected.
+func (ring *Ring[T]) startConvert(removed []uint64, added []uint64) {
+	ring.converting = true
+	ring.convertDone = 0
+	ring.convertTotal = 0
+	if ring.OnConvertProgress == nil {
+		return
+	}
+
+	gone := make(map[uint64]struct{}, len(removed))
+	for _, slice := range removed {
+		_, ok := ring.nodesBySlice[slice]
+		if ok {
+			gone[slice] = struct{}{}
+		}
+	}
+
+	remaining := make([]uint64, 0, len(ring.slices))
+	for _, slice := range ring.slices {
+		_, ok := gone[slice]
+		if !ok {
+			remaining = append(remaining, slice)
+		}
+	}
+
+	if len(remaining) > 0 {
+		for slice := range gone {
+			ring.convertTotal += ring.arcHashCount(ring.slices, findIndex(ring.slices, slice))
+		}
+	}
+
+	sorted := make([]uint64, len(added))
+	copy(sorted, added)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	final := mergeSorted(remaining, sorted)
+	for _, slice := range sorted {
+		ring.convertTotal += ring.arcHashCount(final, findIndex(final, slice))
+	}
+}
+
+// arcHashCount counts the hashes from the slice at the index up to the slice following it, wrapping around
+// the ring, which are every hash if it is the only slice.
+func (ring *Ring[T]) arcHashCount(slices []uint64, idx int) int {
+	slice, nextSlice := slices[idx], slices[findNextIndex(slices, idx)]
+	start, end := findIndex(ring.hashes, slice), findIndex(ring.hashes, nextSlice)
+	if nextSlice <= slice {
+		return len(ring.hashes) - start + end
+	}
+
+	return end - start
+}
+
+func (ring *Ring[T]) convertHash(slice uint64, hash uint64) {
+	prevSlice := ring.slicesByHash[hash]
+
+	// The hash already belongs to the slice, so nothing moves.
+	if prevSlice == slice {
+		return
+	}
+
+	// Defer notification if moves are being collected.
+	if ring.moves != nil {
+		ring.recordMove(hash, owner{node: ring.nodesBySlice[prevSlice]})
+		ring.slicesByHash[hash] = slice
+		return
+	}
+
+	// Notify previous node of removals.
+	for _, key := range ring.unpinnedKeys(hash) {
+		ring.notify(Op[T]{
+			Key:        key.Key,
+			Payload:    ring.contentByKey[key.Key],
+			Node:       ring.nodesBySlice[prevSlice],
+			Removed:    true,
+			RingChange: true,
+		})
+	}
+
+	// Reassign hash's slice and notify addition.
+	ring.slicesByHash[hash] = slice
+	for _, key := range ring.unpinnedKeys(hash) {
+		ring.Metrics.IncMovement(ring.nodesBySlice[slice])
+		ring.notify(Op[T]{
+			Key:        key.Key,
+			Payload:    ring.contentByKey[key.Key],
+			Node:       ring.nodesBySlice[slice],
+			RingChange: true,
+		})
+	}
+}
+
+// owner identifies the node owning a hash, or whether the hash is parked in the empty container.
+type owner struct {
+	node   string
+	parked bool
+}
+
+// recordMove notes the owner of a hash before it first moves while moves are being collected.
+func (ring *Ring[T]) recordMove(hash uint64, prev owner) {
+	_, ok := ring.moves[hash]
+	if !ok {
+		ring.moves[hash] = prev
+	}
+}
+
+// ownerOfHash resolves the current owner of a hash.
+func (ring *Ring[T]) ownerOfHash(hash uint64) owner {
+	_, ok := ring.empty[hash]
+	if ok {
+		return owner{parked: true}
+	}
+
+	return owner{node: ring.nodesBySlice[ring.slicesByHash[hash]]}
+}
+
+// ownerOfKey resolves the current owner of a key with the given hash, which is its pinned node if it is pinned.
+func (ring *Ring[T]) ownerOfKey(key string, hash uint64) owner {
+	node, ok := ring.pinnedKeys[key]
+	if ok {
+		return owner{node: node}
+	}
+
+	return ring.ownerOfHash(hash)
+}
+
+// collectMoves applies the topology change while deferring all movement notifications. Once applied,
+// only the net movement of each affected hash is notified, in ascending hash order, so that keys never
+// bounce through intermediate owners. It returns the number of keys which changed owners.
+func (ring *Ring[T]) collectMoves(change func() error) (int, error) {
+	ring.moves = make(map[uint64]owner)
+	err := change()
+	moves := ring.moves
+	ring.moves = nil
+	ring.converting = false
+	ring.refreshView()
+
+	hashes := make([]uint64, 0, len(moves))
+	for hash := range moves {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+
+	var moved int
+	for _, hash := range hashes {
+		prev, next := moves[hash], ring.ownerOfHash(hash)
+		if prev == next {
+			continue
+		}
+
+		// Pinned keys stay on their pinned nodes regardless of their hashes.
+		keys := ring.unpinnedKeys(hash)
+		moved += len(keys)
+
+		if !prev.parked {
+			for _, key := range keys {
+				ring.notify(Op[T]{
+					Key:        key.Key,
+					Payload:    ring.contentByKey[key.Key],
+					Node:       prev.node,
+					Removed:    true,
+					RingChange: true,
+				})
+			}
+		}
+
+		if !next.parked {
+			for _, key := range keys {
+				ring.Metrics.IncMovement(next.node)
+				ring.notify(Op[T]{
+					Key:        key.Key,
+					Payload:    ring.contentByKey[key.Key],
+					Node:       next.node,
+					RingChange: true,
+				})
+			}
+		}
+	}
+
+	return moved, err
+}
+
+// Emplace attempts to add the given key to the hash ring.
+// If the optional hash key is provided, this will be used to hash the key into the ring.
+// Otherwise, the key itself will be used to hash into the ring.
+// The key must unique; an error will be thrown otherwise.
+func (ring *Ring[T]) Emplace(key *Key[T], hk ...string) error {
+	defer ring.timeOp("Emplace")()
+
+	if key == nil {
+		return ErrNilKey
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	// Check to see if key already exists.
+	_, ok := ring.hashesByKey[key.InnerKey.Key]
+	if ok {
+		return ErrKeyAlreadyExists
+	}
+
+	hashKey := hashKeyOf(key, hk)
+	hash := ring.HashKey(hashKey)
+	err := ring.checkKeyHash(hash)
+	if err != nil {
+		return err
+	}
+	ring.emplace(key, hashKey, hash)
+
+	return nil
+}
+
+// checkKeyHash returns ErrKeyHashCollision if StrictCollisions is set and the hash is the hash of a slice.
+func (ring *Ring[T]) checkKeyHash(hash uint64) error {
+	if !ring.StrictCollisions {
+		return nil
+	}
+
+	_, ok := ring.nodesBySlice[hash]
+	if ok {
+		return ErrKeyHashCollision
+	}
+
+	return nil
+}
+
+// EmplaceBatch attempts to add all of the given keys to the ring at once, notifying each key as Emplace would.
+// The optional hash keys correspond to the keys by index, with an empty hash key meaning the key itself is hashed.
+// Each key succeeds or fails independently, and the returned errors correspond to the keys by index, with a nil
+// error for each key emplaced. ErrBatchLengthMismatch is returned if the hash keys do not correspond to the keys,
+// in which case no key is emplaced. The new hashes are merged into the ring in a single pass, so emplacing many keys
+// at once avoids the per-key shifting of the sorted hashes that Emplace incurs.
+func (ring *Ring[T]) EmplaceBatch(keys []*Key[T], hks ...[]string) ([]error, error) {
+	defer ring.timeOp("EmplaceBatch")()
+
+	var hashKeys []string
+	if len(hks) > 0 {
+		hashKeys = hks[0]
+		if len(hashKeys) != len(keys) {
+			return nil, ErrBatchLengthMismatch
+		}
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	errs := make([]error, len(keys))
+	added := make(map[uint64]struct{})
+	for idx, key := range keys {
+		if key == nil {
+			errs[idx] = ErrNilKey
+			continue
+		}
+
+		_, ok := ring.hashesByKey[key.InnerKey.Key]
+		if ok {
+			errs[idx] = ErrKeyAlreadyExists
+			continue
+		}
+
+		hashKey := key.InnerKey.Key
+		if hashKeys != nil && hashKeys[idx] != "" {
+			hashKey = hashKeys[idx]
+		}
+
+		hash := ring.HashKey(hashKey)
+		errs[idx] = ring.checkKeyHash(hash)
+		if errs[idx] != nil {
+			continue
+		}
+
+		if !ring.hasHash(hash) {
+			added[hash] = struct{}{}
+		}
+		ring.emplaceKey(key, hashKey, hash)
+	}
+
+	hashes := make([]uint64, 0, len(added))
+	for hash := range added {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+	ring.hashes = mergeSorted(ring.hashes, hashes)
+
+	return errs, nil
+}
+
+// hashKeyOf identifies which key will be used to create the hash, which is the optional hash key if provided.
+func hashKeyOf[T any](key *Key[T], hk []string) string {
+	if len(hk) == 0 {
+		return key.InnerKey.Key
+	}
+
+	return hk[0]
+}
+
+// EmplaceColocated attempts to add all of the given keys to the same position of the hash ring,
+// as determined by the hash key, so that every key is always owned by the same node.
+// Keys at the shared position are notified according to their orders.
+// Every key must be unique; if any key is nil or already exists, none of the keys are emplaced.
+func (ring *Ring[T]) EmplaceColocated(hashKey string, keys []*Key[T]) error {
+	defer ring.timeOp("EmplaceColocated")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	// Validate every key before emplacing any of them.
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if key == nil {
+			return ErrNilKey
+		}
+
+		_, ok := ring.hashesByKey[key.InnerKey.Key]
+		if ok {
+			return ErrKeyAlreadyExists
+		}
+
+		_, ok = seen[key.InnerKey.Key]
+		if ok {
+			return ErrKeyAlreadyExists
+		}
+		seen[key.InnerKey.Key] = struct{}{}
+	}
+
+	// Hash once for the entire group.
+	hash := ring.HashKey(hashKey)
+	err := ring.checkKeyHash(hash)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		ring.emplace(key, hashKey, hash)
+	}
+
+	return nil
+}
+
+func (ring *Ring[T]) emplace(key *Key[T], hashKey string, hash uint64) {
+	ring.insertHash(hash)
+	ring.emplaceKey(key, hashKey, hash)
+}
+
+// emplaceKey adds the key at the hash, which must already be, or later be, inserted into the sorted hashes.
+func (ring *Ring[T]) emplaceKey(key *Key[T], hashKey string, hash uint64) {
+	ring.Metrics.IncEmplace()
+
+	// Insert key content into keysByKey map.
+	ring.contentByKey[key.InnerKey.Key] = key.Value
+	if hashKey != key.InnerKey.Key {
+		ring.hashKeyByKey[key.InnerKey.Key] = hashKey
+	}
+	ring.index(key.InnerKey.Key, key.Value)
+	ring.touchedByKey[key.InnerKey.Key] = ring.Clock.Now()
+
+	// Check to see if there are any slices to take the key.
+	if len(ring.slices) == 0 {
+		ring.empty[hash] = hash
+
+		ring.notify(Op[T]{
+			Key:     key.InnerKey.Key,
+			Payload: key.Value,
+		})
+	} else {
+		// Find the appropriate slice this hash will belong to.
+		slice := ring.slices[findPrevIndex(ring.slices, findIndex(ring.slices, hash))]
+		ring.slicesByHash[hash] = slice
+
+		ring.notify(Op[T]{
+			Key:     key.InnerKey.Key,
+			Node:    ring.nodesBySlice[slice],
+			Payload: key.Value,
+		})
+	}
+
+	// Insert key into keys array for this hash.
+	ring.insertKey(hash, key.InnerKey)
+
+	// Insert key into hashes by key table.
+	ring.hashesByKey[key.InnerKey.Key] = hash
+	ring.publishKey(key.InnerKey.Key)
+}
+
+// Update attempts to update the key object in the ring without changing
+// its position in the ring, or its hash.
+func (ring *Ring[T]) Update(key *Key[T]) error {
+	defer ring.timeOp("Update")()
+
+	if key == nil {
+		return ErrNilKey
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	// Assure key is actually present in ring.
+	_, ok := ring.hashesByKey[key.InnerKey.Key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	ring.update(key.InnerKey.Key, key.Value)
+
+	return nil
+}
+
+// EmplaceOrUpdate emplaces the key as Emplace would if it is not in the ring, and otherwise updates its payload
+// as Update would, without moving it. The optional hash key is only used when the key is emplaced.
+func (ring *Ring[T]) EmplaceOrUpdate(key *Key[T], hk ...string) error {
+	defer ring.timeOp("EmplaceOrUpdate")()
+
+	if key == nil {
+		return ErrNilKey
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	_, ok := ring.hashesByKey[key.InnerKey.Key]
+	if ok {
+		ring.update(key.InnerKey.Key, key.Value)
+		return nil
+	}
+
+	hashKey := hashKeyOf(key, hk)
+	hash := ring.HashKey(hashKey)
+	err := ring.checkKeyHash(hash)
+	if err != nil {
+		return err
+	}
+	ring.emplace(key, hashKey, hash)
+
+	return nil
+}
+
+// UpdateBatch attempts to update the payloads of all of the given keys at once, notifying an update for each key
+// in the order given. Every key must be present, otherwise none of the keys are updated and an error wrapping
+// ErrKeyNotFound identifies the first missing key.
+func (ring *Ring[T]) UpdateBatch(keys []*Key[T]) error {
+	defer ring.timeOp("UpdateBatch")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	// Validate every key before updating any of them.
+	for _, key := range keys {
+		if key == nil {
+			return ErrNilKey
+		}
+
+		_, ok := ring.hashesByKey[key.InnerKey.Key]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrKeyNotFound, key.InnerKey.Key)
+		}
+	}
+
+	for _, key := range keys {
+		ring.update(key.InnerKey.Key, key.Value)
+	}
+
+	return nil
+}
+
+// UpdateBatchBestEffort updates the payloads of the given keys at once like UpdateBatch, except that missing keys
+// are skipped rather than preventing the update, and are returned in the order given. Nil keys are ignored.
+func (ring *Ring[T]) UpdateBatchBestEffort(keys []*Key[T]) (missing []string) {
+	defer ring.timeOp("UpdateBatchBestEffort")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	for _, key := range keys {
+		if key == nil {
+			continue
+		}
+
+		_, ok := ring.hashesByKey[key.InnerKey.Key]
+		if !ok {
+			missing = append(missing, key.InnerKey.Key)
+			continue
+		}
+
+		ring.update(key.InnerKey.Key, key.Value)
+	}
+
+	return missing
+}
+
+// CompareAndUpdate attempts to replace the payload of the key only if its current payload is equal to the expected payload,
+// as determined by the provided equality function. An update is only notified if the payload is replaced.
+func (ring *Ring[T]) CompareAndUpdate(key string, expected, value T, eq func(a, b T) bool) error {
+	defer ring.timeOp("CompareAndUpdate")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	_, ok := ring.hashesByKey[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	if !eq(ring.contentByKey[key], expected) {
+		return ErrCASMismatch
+	}
+
+	ring.update(key, value)
+
+	return nil
+}
+
+func (ring *Ring[T]) update(key string, value T) {
+
+	// Update key in keysByKey map, moving it within the secondary index if necessary.
+	ring.unindex(key, ring.contentByKey[key])
+	ring.contentByKey[key] = value
+	ring.index(key, value)
+
+	// Notify subscribers of key update.
+	node, _ := ring.nodeForKey(key)
+	ring.notify(Op[T]{
+		Key:     key,
+		Payload: value,
+		Node:    node,
+		Updated: true,
+	})
+}
+
+// Remove will remove a key from the ring, given its unique key.
+func (ring *Ring[T]) Remove(key string) {
+	defer ring.timeOp("Remove")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	ring.remove(key)
+}
+
+// RemoveErr will remove a key from the ring as Remove would, returning ErrKeyNotFound if the key does not exist.
+func (ring *Ring[T]) RemoveErr(key string) error {
+	defer ring.timeOp("RemoveErr")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	_, ok := ring.hashesByKey[key]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	ring.remove(key)
+
+	return nil
+}
+
+// RemoveBatch will remove all of the given keys from the ring at once, notifying each removal as Remove would.
+// Keys which don't exist are skipped.
+func (ring *Ring[T]) RemoveBatch(keys []string) {
+	defer ring.timeOp("RemoveBatch")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	for _, key := range keys {
+		ring.remove(key)
+	}
+}
+
+// DrainOrphans removes every key held in the empty container from the ring and returns the removed keys,
+// allowing them to be routed elsewhere. Keys are returned in the order their removals were notified.
+func (ring *Ring[T]) DrainOrphans() []string {
+	defer ring.timeOp("DrainOrphans")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	// Collect first, as removal modifies the hashes being iterated.
+	var keys []string
+	for _, hash := range ring.hashes {
+		_, ok := ring.empty[hash]
+		if !ok {
+			continue
+		}
+
+		for _, key := range ring.keysByHash[hash] {
+			keys = append(keys, key.Key)
+		}
+	}
+
+	for _, key := range keys {
+		ring.remove(key)
+	}
+
+	return keys
+}
+
+// Clear removes every key and node from the ring in place, notifying watchers of each key's removal as Remove
+// would. Watchers and event streams remain registered, although no node or slice events are emitted.
+func (ring *Ring[T]) Clear() {
+	defer ring.timeOp("Clear")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	// Collect first, as removal modifies the hashes being iterated.
+	var keys []string
+	for _, hash := range ring.hashes {
+		for _, key := range ring.keysByHash[hash] {
+			keys = append(keys, key.Key)
+		}
+	}
+
+	for _, key := range keys {
+		ring.remove(key)
+	}
+
+	// An empty snapshot is always consistent.
+	_ = ring.restore(&snapshot[T]{})
+}
+
+// RestorePayload returns the payload most recently removed with the given key, if it is still retained.
+// This allows the payload of a key which is removed and then quickly emplaced again to be reused.
+// Nothing is ever retained unless RetainRemovedPayloads is set.
+func (ring *Ring[T]) RestorePayload(key string) (T, bool) {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	if ring.retained == nil {
+		var payload T
+		return payload, false
+	}
+
+	return ring.retained.get(key)
+}
+
+func (ring *Ring[T]) remove(key string) {
+
+	// Noop if the key doesn't exist.
+	hash, ok := ring.hashesByKey[key]
+	if !ok {
+		return
+	}
+	ring.Metrics.IncRemove()
+
+	// Delete from keysByKey map, retaining the payload if configured.
+	payload := ring.contentByKey[key]
+	ring.unindex(key, payload)
+	if ring.retained != nil {
+		ring.retained.put(key, payload)
+	}
+	delete(ring.contentByKey, key)
+	delete(ring.touchedByKey, key)
+	delete(ring.hashKeyByKey, key)
+	ring.forgetExpiry(key)
+
+	// Remove the key from the keys by hash table for this hash, shifting the keys after it to keep them in order.
+	ring.keysByHash[hash], _ = removeIndex(
+		ring.keysByHash[hash],
+		ring.findKeyByName(hash, key),
+	)
+	delete(ring.rankByKey, key)
+
+	// The key is removed from its pinned node, if any, and the pin is dropped with it.
+	node := ring.ownerOfKey(key, hash).node
+	delete(ring.pinnedKeys, key)
+
+	// Notify key removal, from the empty node if the key is held in the empty container.
+	ring.notify(Op[T]{
+		Key:     key,
+		Node:    node,
+		Payload: payload,
+		Removed: true,
+	})
+
+	// If this was the last key left for this hash, remove the hash.
+	if len(ring.keysByHash[hash]) == 0 {
+		// Remove the hash.
+		ring.removeHash(hash)
+
+		// Remove from slices by hash table, or from the empty container.
+		delete(ring.slicesByHash, hash)
+		delete(ring.empty, hash)
+	}
+
+	// Delete key from hashes by key table/
+	delete(ring.hashesByKey, key)
+	ring.publishKey(key)
+}
+
+// AssertOwner returns an error describing the actual owner of the key if it is not currently owned by the expected node.
+// Keys held in the empty container are owned by the empty node identifier.
+func (ring *Ring[T]) AssertOwner(key, expectedNode string) error {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	node, ok := ring.nodeForKey(key)
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	if node != expectedNode {
+		return fmt.Errorf("%w: key %q is owned by node %q, expected node %q", ErrUnexpectedOwner, key, node, expectedNode)
+	}
+
+	return nil
+}
+
+// Lookup resolves the node which a key hashed with the hash key would be assigned to, without emplacing anything.
+// It reports false if the ring has no slices to assign the key to.
+//
+// Lookup never locks the ring, reading an immutable copy of the topology which is replaced once each topology
+// change completes. It is therefore eventually consistent: a Lookup concurrent with a topology change resolves
+// against the topology from before the change until the change returns, and never against a partial change.
+// DrainNode publishes the topology once per slice drained, as each slice is a change of its own.
+func (ring *Ring[T]) Lookup(hashKey string) (string, bool) {
+	view := ring.view.Load()
+
+	return view.lookup(view.hashKey(hashKey))
+}
+
+// PendingKeys lists the keys held in the empty container, which are assigned to no node until a slice is inserted.
+// Keys are listed in ascending hash order and then by order. Pinned keys are assigned to their pinned nodes, so
+// are never pending. The list is empty whenever the ring has slices.
+func (ring *Ring[T]) PendingKeys() []string {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	keys := []string{}
+	for _, hash := range ring.hashes {
+		_, ok := ring.empty[hash]
+		if !ok {
+			continue
+		}
+
+		for _, key := range ring.unpinnedKeys(hash) {
+			keys = append(keys, key.Key)
+		}
 	}
 
-	// Remove the slice from the slices array.
-	ring.slices, _ = removeIndex(ring.slices, sliceIdx)
+	return keys
+}
 
-	// Delete from nodes by slice map.
-	delete(ring.nodesBySlice, slice)
+// GetNodeForKey provides the identifier of the node currently owning the key, without changing the ring.
+// Keys held in the empty container are owned by the empty node identifier.
+//
+// Like Lookup, GetNodeForKey never locks the ring, resolving the hash and pin published for the key against the
+// same immutable copy of the topology. It is therefore eventually consistent in the same way: a call concurrent
+// with a topology change resolves against the topology from before the change until the change returns.
+func (ring *Ring[T]) GetNodeForKey(key string) (string, error) {
+	value, ok := ring.keyViews.Load().Load(key)
+	if !ok {
+		return "", ErrKeyNotFound
+	}
+
+	placement := value.(keyView)
+	if placement.pinned {
+		return placement.node, nil
+	}
+
+	node, _ := ring.view.Load().lookup(placement.hash)
+	return node, nil
 }
 
-func (ring *Ring[T]) convertHashes(
-	slice uint64,
-	hashStartIndex int,
-	hashEndIndex int,
-	circle bool,
-) {
+// RegisterWatcherWithReplay provides a channel of Ops like RegisterWatcher, which first receives an op for every key
+// currently in the ring matching the filter, as though each key had just been emplaced. Keys are replayed in
+// ascending hash order and then by order, and no change to the ring can occur between the replay and registration,
+// so the watcher observes every key exactly once. The channel has capacity for every replayed op, in addition to
+// the WatcherBuffer.
+func (ring *Ring[T]) RegisterWatcherWithReplay(filter Op[T]) chan Op[T] {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
 
-	// If start and end are equal, convert the entire ring.
-	if circle && hashStartIndex == hashEndIndex ||
-		hashStartIndex == 0 && hashEndIndex == len(ring.hashes) {
-		for _, hash := range ring.hashes {
-			ring.convertHash(slice, hash)
+	registration := ring.Filter(filter)
+
+	var replay []Op[T]
+	for _, hash := range ring.hashes {
+		for _, key := range ring.keysByHash[hash] {
+			op := Op[T]{
+				Key:     key.Key,
+				Node:    ring.ownerOfKey(key.Key, hash).node,
+				Payload: ring.contentByKey[key.Key],
+			}
+			if ring.Filter(op) == registration {
+				replay = append(replay, op)
+			}
 		}
-		return
 	}
 
-	if hashEndIndex == len(ring.hashes) {
-		hashEndIndex = 0
+	opChans := ring.registerBuffered(filter, WatcherOptions{}, ring.WatcherBuffer+len(replay))
+	for _, op := range replay {
+		opChans.msg <- op
 	}
 
-	// Otherwise convert only the specified range.
-	for {
-		if hashStartIndex == len(ring.hashes) {
-			hashStartIndex = 0
-		}
-
-		if hashStartIndex == hashEndIndex {
-			return
-		}
+	return opChans.msg
+}
 
-		ring.convertHash(slice, ring.hashes[hashStartIndex])
+// GetPayload provides the payload currently stored with the key, as last set by Emplace or Update.
+func (ring *Ring[T]) GetPayload(key string) (T, error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
 
-		hashStartIndex++
+	payload, ok := ring.contentByKey[key]
+	if !ok {
+		var zero T
+		return zero, ErrKeyNotFound
 	}
 
+	return payload, nil
 }
 
-func (ring *Ring[T]) convertHash(slice uint64, hash uint64) {
+// Contains reports whether the key is in the ring, including keys held in the empty container.
+func (ring *Ring[T]) Contains(key string) bool {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
 
-	// Notify previous node of removals.
-	prevSlice := ring.slicesByHash[hash]
-	for _, key := range ring.keysByHash[hash] {
-		ring.notify(Op[T]{
-			Key:        key.Key,
-			Payload:    ring.contentByKey[key.Key],
-			Node:       ring.nodesBySlice[prevSlice],
-			Removed:    true,
-			RingChange: true,
-		})
-	}
+	_, ok := ring.hashesByKey[key]
+	return ok
+}
 
-	// Reassign hash's slice and notify addition.
-	ring.slicesByHash[hash] = slice
-	for _, key := range ring.keysByHash[hash] {
-		ring.notify(Op[T]{
-			Key:        key.Key,
-			Payload:    ring.contentByKey[key.Key],
-			Node:       ring.nodesBySlice[slice],
-			RingChange: true,
-		})
+// RangeKeys calls fn with every key in the ring, along with the node owning it and its payload, until fn
+// returns false. Keys are visited in no particular order, and keys held in the empty container are owned by the
+// empty node identifier. The ring is read locked throughout, so fn must not call any method mutating the ring.
+func (ring *Ring[T]) RangeKeys(fn func(key string, node string, payload T) bool) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	for key := range ring.hashesByKey {
+		node, _ := ring.nodeForKey(key)
+		if !fn(key, node, ring.contentByKey[key]) {
+			return
+		}
 	}
 }
 
-// Emplace attempts to add the given key to the hash ring.
-// If the optional hash key is provided, this will be used to hash the key into the ring.
-// Otherwise, the key itself will be used to hash into the ring.
-// The key must unique; an error will be thrown otherwise.
-func (ring *Ring[T]) Emplace(key *Key[T], hk ...string) error {
-	if key == nil {
-		return ErrNilKey
-	}
+// Route resolves the complete routing of the key: the node owning it, the slice governing its position, and the
+// hash of the key itself. Keys held in the empty container are owned by the empty node identifier and governed
+// by their own hash, as no slice exists. Pinned keys are governed by the closest slice of their pinned node
+// preceding their hash.
+func (ring *Ring[T]) Route(key string) (node string, slice uint64, hash uint64, err error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
 
-	ring.mu.Lock()
-	defer ring.mu.Unlock()
+	hash, ok := ring.hashesByKey[key]
+	if !ok {
+		return "", 0, 0, ErrKeyNotFound
+	}
 
-	// Check to see if key already exists.
-	_, ok := ring.hashesByKey[key.InnerKey.Key]
+	node, ok = ring.pinnedKeys[key]
 	if ok {
-		return ErrKeyAlreadyExists
+		return node, ring.pinnedSlice(node, hash), hash, nil
 	}
 
-	// Insert key content into keysByKey map.
-	ring.contentByKey[key.InnerKey.Key] = key.Value
-
-	// Identify which key will be used to create the hash.
-	var hashKey string
-	if len(hk) == 0 {
-		hashKey = key.InnerKey.Key
-	} else {
-		hashKey = hk[0]
+	slice, ok = ring.empty[hash]
+	if ok {
+		return "", slice, hash, nil
 	}
 
-	// Hash the key.
-	hash := ring.Hash(hashKey)
+	slice = ring.slicesByHash[hash]
+	return ring.nodesBySlice[slice], slice, hash, nil
+}
 
-	// Insert into hash ring.
-	ring.insertHash(hash)
+// Explain resolves why the key is owned by its node, as the hash of the key, the slice owning that hash, and the
+// node owning that slice. It is equivalent to Route, ordered from the key to its node. For keys held in the empty
+// container, the slice is the hash itself and the node is empty.
+func (ring *Ring[T]) Explain(key string) (hash uint64, slice uint64, node string, err error) {
+	node, slice, hash, err = ring.Route(key)
+	return hash, slice, node, err
+}
+
+// NodesFrom lists the distinct nodes in the order their slices appear clockwise around the ring, starting with the
+// node of the slice owning the hash and wrapping around the ring once. It is empty if the ring has no slices.
+func (ring *Ring[T]) NodesFrom(hash uint64) []string {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
 
-	// Check to see if there are any slices to take the key.
 	if len(ring.slices) == 0 {
-		ring.empty[hash] = hash
+		return []string{}
+	}
 
-		ring.notify(Op[T]{
-			Key:     key.InnerKey.Key,
-			Payload: key.Value,
-		})
-	} else {
-		// Find the appropriate slice this hash will belong to.
-		slice := ring.slices[findPrevIndex(ring.slices, findIndex(ring.slices, hash))]
-		ring.slicesByHash[hash] = slice
+	return ring.distinctNodesFrom(findPrevIndex(ring.slices, findIndex(ring.slices, hash)), 0)
+}
 
-		ring.notify(Op[T]{
-			Key:     key.InnerKey.Key,
-			Node:    ring.nodesBySlice[slice],
-			Payload: key.Value,
-		})
+// Replicas lists up to n distinct nodes responsible for the key, starting with the node owning it and continuing with
+// the nodes of the slices following its slice clockwise around the ring, so that the virtual slices of a node already
+// listed are skipped. Fewer than n nodes are listed if the ring has fewer nodes, and none if the key is held in the
+// empty container. No two slices share a hash, so the walk never has to choose between candidates, and rings sharing
+// a topology and hash function always agree on the replicas of a key.
+func (ring *Ring[T]) Replicas(key string, n int) ([]string, error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	hash, ok := ring.hashesByKey[key]
+	if !ok {
+		return nil, ErrKeyNotFound
 	}
 
-	// Insert key into keys array for this hash.
-	ring.keysByHash[hash], _ = insertPreserveOrder(
-		ring.keysByHash[hash],
-		key.InnerKey,
-		findKeyIndex,
-	)
+	_, ok = ring.empty[hash]
+	if ok || n < 1 {
+		return []string{}, nil
+	}
 
-	// Insert key into hashes by key table.
-	ring.hashesByKey[key.InnerKey.Key] = hash
+	return ring.distinctNodesFrom(findIndex(ring.slices, ring.slicesByHash[hash]), n), nil
+}
 
-	return nil
+// ReplicasForHashKey lists up to n distinct nodes responsible for any key hashed with the hash key, as Replicas would
+// once such a key is emplaced, without emplacing anything. None are listed if the ring has no slices.
+func (ring *Ring[T]) ReplicasForHashKey(hashKey string, n int) []string {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	if len(ring.slices) == 0 || n < 1 {
+		return []string{}
+	}
+
+	hash := ring.HashKey(hashKey)
+	return ring.distinctNodesFrom(findPrevIndex(ring.slices, findIndex(ring.slices, hash)), n)
 }
 
-// Update attempts to update the key object in the ring without changing
-// its position in the ring, or its hash.
-func (ring *Ring[T]) Update(key *Key[T]) error {
-	if key == nil {
-		return ErrNilKey
+// distinctNodesFrom walks the slices clockwise from the slice at the index, wrapping around the ring once, and lists
+// each node the first time one of its slices is visited. The walk stops early once limit nodes are listed, unless
+// the limit is zero.
+func (ring *Ring[T]) distinctNodesFrom(idx int, limit int) []string {
+	nodes := []string{}
+	seen := make(map[string]struct{})
+	for range ring.slices {
+		node := ring.nodesBySlice[ring.slices[idx]]
+
+		_, ok := seen[node]
+		if !ok {
+			seen[node] = struct{}{}
+			nodes = append(nodes, node)
+			if len(nodes) == limit {
+				break
+			}
+		}
+
+		idx = findNextIndex(ring.slices, idx)
 	}
 
-	// Assure key is actually present in ring.
-	_, ok := ring.contentByKey[key.InnerKey.Key]
+	return nodes
+}
+
+// nodeForKey resolves the node currently owning the key, which is empty for keys in the empty container
+// unless they are pinned.
+func (ring *Ring[T]) nodeForKey(key string) (string, bool) {
+	hash, ok := ring.hashesByKey[key]
 	if !ok {
-		return ErrKeyNotFound
+		return "", false
 	}
 
-	// Update key in keysByKey map.
-	ring.contentByKey[key.InnerKey.Key] = key.Value
+	node, ok := ring.pinnedKeys[key]
+	if ok {
+		return node, true
+	}
 
-	// Notify subscribers of key update.
-	ring.notify(Op[T]{
-		Key:     key.InnerKey.Key,
-		Payload: key.Value,
-		Node:    ring.nodesBySlice[ring.slicesByHash[ring.hashesByKey[key.InnerKey.Key]]],
-		Updated: true,
-	})
+	_, ok = ring.empty[hash]
+	if ok {
+		return "", true
+	}
 
-	return nil
+	return ring.nodesBySlice[ring.slicesByHash[hash]], true
 }
 
-// Remove will remove a key from the ring, given its unique key.
-func (ring *Ring[T]) Remove(key string) {
+// Touch records that the key was accessed at the current time of the ring's Clock.
+// Keys are considered touched when emplaced. It is a noop if the key does not exist.
+func (ring *Ring[T]) Touch(key string) {
+	defer ring.timeOp("Touch")()
+
 	ring.mu.Lock()
 	defer ring.mu.Unlock()
 
-	// Noop if the key doesn't exist.
-	hash, ok := ring.hashesByKey[key]
+	_, ok := ring.hashesByKey[key]
 	if !ok {
 		return
 	}
 
-	// Delete from keysByKey map.
-	delete(ring.contentByKey, key)
+	ring.touchedByKey[key] = ring.Clock.Now()
+}
 
-	// Remove the key from the keys by hash table for this hash.
-	ring.keysByHash[hash], _ = removeIndex(
-		ring.keysByHash[hash],
-		findKeyByName(ring.keysByHash[hash], key),
-	)
+// CountWeightedByRecency sums, per node, the weight of each key owned by that node, where a key's weight
+// halves for every half-life elapsed since it was last touched. Keys in the empty container are counted
+// under the empty node identifier. A non-positive half-life applies no decay.
+func (ring *Ring[T]) CountWeightedByRecency(halfLife time.Duration) map[string]float64 {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
 
-	// If the empty container has any elements, remove from the empty container.
-	if len(ring.empty) > 0 {
-		delete(ring.empty, hash)
+	now := ring.Clock.Now()
 
-		ring.notify(Op[T]{
-			Key:     key,
-			Removed: true,
-		})
-	} else {
+	counts := make(map[string]float64, len(ring.vFactorByNode))
+	for node := range ring.vFactorByNode {
+		counts[node] = 0
+	}
 
-		// Notify new key removal from ring.
-		ring.notify(Op[T]{
+	for key := range ring.hashesByKey {
+		node, _ := ring.nodeForKey(key)
+
+		weight := 1.0
+		if halfLife > 0 {
+			weight = math.Exp2(-float64(now.Sub(ring.touchedByKey[key])) / float64(halfLife))
+		}
+
+		counts[node] += weight
+	}
+
+	return counts
+}
+
+// UnwatchedKeys lists, in sorted order, the keys whose add ops would not currently match any registered watcher,
+// meaning that nobody is observing their movements. It is empty while any channel provided by WatchAll is
+// registered, as those observe every key.
+func (ring *Ring[T]) UnwatchedKeys() []string {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	ring.watchMu.Lock()
+	defer ring.watchMu.Unlock()
+
+	keys := []string{}
+	if len(ring.all) > 0 {
+		return keys
+	}
+
+	for key := range ring.hashesByKey {
+		node, _ := ring.nodeForKey(key)
+
+		_, ok := ring.watchers[ring.Filter(Op[T]{
 			Key:     key,
-			Node:    ring.nodesBySlice[ring.slicesByHash[hash]],
-			Removed: true,
-		})
+			Node:    node,
+			Payload: ring.contentByKey[key],
+		})]
+		if !ok {
+			keys = append(keys, key)
+		}
 	}
 
-	// If this was the last key left for this hash, remove the hash.
-	if len(ring.keysByHash[hash]) == 0 {
-		// Remove the hash.
-		ring.removeHash(hash)
+	sort.Strings(keys)
 
-		// Remove from slices by hash table.
-		delete(ring.slicesByHash, hash)
+	return keys
+}
+
+// FindBySecondary lists the keys whose payloads produced the provided value under IndexBy.
+// The result is always empty if IndexBy is not set.
+func (ring *Ring[T]) FindBySecondary(value string) []string {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	keys := make([]string, len(ring.keysByIndex[value]))
+	copy(keys, ring.keysByIndex[value])
+
+	return keys
+}
+
+func (ring *Ring[T]) index(key string, payload T) {
+	if ring.IndexBy == nil {
+		return
 	}
 
-	// Delete key from hashes by key table/
-	delete(ring.hashesByKey, key)
+	value := ring.IndexBy(payload)
+	ring.keysByIndex[value] = append(ring.keysByIndex[value], key)
+}
+
+func (ring *Ring[T]) unindex(key string, payload T) {
+	if ring.IndexBy == nil {
+		return
+	}
+
+	value := ring.IndexBy(payload)
+	for idx, indexed := range ring.keysByIndex[value] {
+		if indexed == key {
+			ring.keysByIndex[value], _ = removeIndex(ring.keysByIndex[value], idx)
+			break
+		}
+	}
+
+	// Drop the entry once no keys remain for this value.
+	if len(ring.keysByIndex[value]) == 0 {
+		delete(ring.keysByIndex, value)
+	}
 }
 
+// insertHash inserts a new hash into the sorted hashes, shifting every hash after it, which is O(n) in the number
+// of distinct hashes. Inserting many hashes at once should merge them with mergeSorted instead.
 func (ring *Ring[T]) insertHash(hash uint64) {
-	idx := findIndex(ring.hashes, hash)
-	if idx >= len(ring.hashes) || ring.hashes[idx] != hash {
+	if !ring.hasHash(hash) {
 		ring.hashes, _ = insertPreserveOrder(ring.hashes, hash, findIndex)
 	}
 }
 
+func (ring *Ring[T]) hasHash(hash uint64) bool {
+	idx := findIndex(ring.hashes, hash)
+	return idx < len(ring.hashes) && ring.hashes[idx] == hash
+}
+
+// removeHash removes a hash from the sorted hashes, shifting every hash after it, which is O(n) in the number of
+// distinct hashes.
 func (ring *Ring[T]) removeHash(hash uint64) {
 	idx := findIndex(ring.hashes, hash)
 	if idx < len(ring.hashes) && ring.hashes[idx] == hash {
@@ -651,23 +3179,63 @@ func findKeyIndex(t []*InnerKey, k *InnerKey) int {
 	)
 }
 
-func findKeyByName(keys []*InnerKey, name string) int {
-	for idx, key := range keys {
-		if key.Key == name {
-			return idx
-		}
+// keyRank is the position of a key among the keys sharing its hash. Keys are sorted by ascending order,
+// and then by descending sequence number, as each key is inserted ahead of any keys with the same order.
+type keyRank struct {
+	order int
+	seq   uint64
+}
+
+// before reports whether a key with this rank is sorted before a key with the other rank.
+func (r keyRank) before(other keyRank) bool {
+	if r.order != other.order {
+		return r.order < other.order
+	}
+
+	return r.seq > other.seq
+}
+
+// insertKey inserts the key into the keys array for the hash, ahead of any keys with the same order,
+// recording its rank so that its position can later be found by binary search.
+func (ring *Ring[T]) insertKey(hash uint64, key *InnerKey) {
+	ring.seq++
+	ring.rankByKey[key.Key] = keyRank{order: key.Order, seq: ring.seq}
+
+	ring.keysByHash[hash], _ = insertPreserveOrder(
+		ring.keysByHash[hash],
+		key,
+		findKeyIndex,
+	)
+}
+
+// findKeyByName will return the index of the named key in the keys array for the hash, or -1 if it is not present.
+// Finding the key takes O(log n) in the number of keys sharing the hash, but inserting or removing it still shifts
+// every key after it, which is O(n): the keys must stay sorted by order, so they cannot be swapped into place.
+func (ring *Ring[T]) findKeyByName(hash uint64, name string) int {
+	rank, ok := ring.rankByKey[name]
+	if !ok {
+		return -1
 	}
 
-	return -1
+	keys := ring.keysByHash[hash]
+	idx := sort.Search(len(keys), func(i int) bool {
+		return !ring.rankByKey[keys[i].Key].before(rank)
+	})
+	if idx == len(keys) || keys[idx].Key != name {
+		return -1
+	}
+
+	return idx
 }
 
 // findIndex will return the index where val is located, or should be inserted (if it is not located in the array).
-func findIndex(arr []uint64, val uint64) int {
+// It is generic over the ordered hash type so that the ring's array logic is not tied to uint64 hashes.
+func findIndex[H cmp.Ordered](arr []H, val H) int {
 	return sort.Search(len(arr), func(i int) bool { return arr[i] >= val })
 }
 
 // findPrevIndex will return the index previous to the current index.
-func findPrevIndex(arr []uint64, idx int) int {
+func findPrevIndex[H any](arr []H, idx int) int {
 	if idx == 0 {
 		return len(arr) - 1
 	}
@@ -675,7 +3243,8 @@ func findPrevIndex(arr []uint64, idx int) int {
 	return idx - 1
 }
 
-func findNextIndex(arr []uint64, idx int) int {
+// findNextIndex will return the index following the current index, wrapping around to the start.
+func findNextIndex[H any](arr []H, idx int) int {
 	if idx == len(arr)-1 {
 		return 0
 	}
@@ -698,6 +3267,21 @@ func insertPreserveOrder[T any](
 	return arr, idx
 }
 
+// mergeSorted merges two sorted arrays into a new sorted array.
+func mergeSorted[H cmp.Ordered](a []H, b []H) []H {
+	merged := make([]H, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if a[0] < b[0] {
+			merged, a = append(merged, a[0]), a[1:]
+		} else {
+			merged, b = append(merged, b[0]), b[1:]
+		}
+	}
+	merged = append(merged, a...)
+
+	return append(merged, b...)
+}
+
 func removeIndex[T any](arr []T, idx int) ([]T, error) {
 	if len(arr) == 0 {
 		return nil, nil
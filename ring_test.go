@@ -1,8 +1,15 @@
 package ring
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -13,6 +20,39 @@ func TestMain(m *testing.M) {
 
 type RingPayloadType struct{}
 
+// testHashes are the hashes which tests independent of any particular hash run against. FNV1a is stable
+// alongside the default MD5, so that tests of the ring's logic cannot come to rely on the output of any one hash.
+var testHashes = map[string]func(string) uint64{
+	"MD5":   MD5,
+	"FNV1a": FNV1a,
+}
+
+// injectedHash places each identifier with an injected position at that position, and hashes any other
+// identifier with the fallback hash, so that tests can choose exactly where keys and slices land.
+func injectedHash(fallback func(string) uint64, positions map[string]uint64) func(string) uint64 {
+	return func(identifier string) uint64 {
+		position, ok := positions[identifier]
+		if ok {
+			return position
+		}
+		return fallback(identifier)
+	}
+}
+
+// newTestRing creates a ring using the hash, before applying any other options.
+func newTestRing[T any](t *testing.T, hash func(string) uint64, options ...func(*Ring[T])) *Ring[T] {
+	ring, err := New(append([]func(*Ring[T]){func(r *Ring[T]) { r.Hash = hash }}, options...)...)
+	require.NoError(t, err)
+	return ring
+}
+
+// keyPositions injects positions for the keys emplaced by the key emplacement tests.
+var keyPositions = map[string]uint64{
+	"1": 100,
+	"2": 200,
+	"3": 300,
+}
+
 func TestInsertHashes(t *testing.T) {
 	ring, err := New(func(r *Ring[RingPayloadType]) {
 		r.BaseVFactor = 1
@@ -304,15 +344,24 @@ func TestEmptyConvertHashes(t *testing.T) {
 func TestSingleConvertHashes(t *testing.T) {
 	ring, err := New(func(r *Ring[RingPayloadType]) {
 		r.BaseVFactor = 1
+		r.WatcherBuffer = 4
 	})
 	require.NoError(t, err)
 
 	ring.hashes = []uint64{1}
 	ring.slicesByHash[1] = 0
+	ring.nodesBySlice[0] = "A"
+	ring.keysByHash[1] = []*InnerKey{{Key: "1"}}
+
+	c := ring.RegisterWatcher(Op[RingPayloadType]{Node: "A"})
 
 	ring.convertHashes(2, 0, 0, false)
+	ring.convertHash(0, 1)
 
 	require.Equal(t, map[uint64]uint64{1: 0}, ring.slicesByHash)
+
+	// Self conversions are not notified.
+	require.Empty(t, c)
 }
 
 func TestSingleConvertHashesWithCircling(t *testing.T) {
@@ -387,14 +436,20 @@ func TestInsertSliceNoHashes(t *testing.T) {
 }
 
 func TestKeyEmplacement(t *testing.T) {
-	ring, err := New(func(r *Ring[RingPayloadType]) {
-		r.BaseVFactor = 1
+	for name, hash := range testHashes {
+		t.Run(name, func(t *testing.T) {
+			testKeyEmplacement(t, hash)
+		})
+	}
+}
 
+func testKeyEmplacement(t *testing.T, hash func(string) uint64) {
+	ring := newTestRing(t, injectedHash(hash, keyPositions), func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 1
 	})
-	require.NoError(t, err)
 
 	// Must insert at least one slice to emplace keys.
-	err = ring.insertSlice(1, "A")
+	err := ring.insertSlice(1, "A")
 	require.NoError(t, err)
 
 	require.Equal(t, []uint64{1}, ring.slices)
@@ -426,23 +481,29 @@ func TestKeyEmplacement(t *testing.T) {
 	require.Equal(
 		t,
 		[]uint64{
-			14180219187711517570,
-			14420089009441877859,
-			17062952057979069182,
+			100,
+			200,
+			300,
 		},
 		ring.hashes,
 	)
 }
 
 func TestKeyEmplacementAndRemovalAndSliceInsertionAndRemoval(t *testing.T) {
-	ring, err := New(func(r *Ring[RingPayloadType]) {
-		r.BaseVFactor = 1
+	for name, hash := range testHashes {
+		t.Run(name, func(t *testing.T) {
+			testKeyEmplacementAndRemovalAndSliceInsertionAndRemoval(t, hash)
+		})
+	}
+}
 
+func testKeyEmplacementAndRemovalAndSliceInsertionAndRemoval(t *testing.T, hash func(string) uint64) {
+	ring := newTestRing(t, injectedHash(hash, keyPositions), func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 1
 	})
-	require.NoError(t, err)
 
 	// Must insert at least one slice to emplace keys.
-	err = ring.insertSlice(1, "A")
+	err := ring.insertSlice(1, "A")
 	require.NoError(t, err)
 
 	require.Equal(t, []uint64{1}, ring.slices)
@@ -474,98 +535,98 @@ func TestKeyEmplacementAndRemovalAndSliceInsertionAndRemoval(t *testing.T) {
 	require.Equal(
 		t,
 		[]uint64{
-			14180219187711517570,
-			14420089009441877859,
-			17062952057979069182,
+			100,
+			200,
+			300,
 		},
 		ring.hashes,
 	)
 
 	require.Equal(t, map[uint64]uint64{
-		14180219187711517570: 1,
-		14420089009441877859: 1,
-		17062952057979069182: 1,
+		100: 1,
+		200: 1,
+		300: 1,
 	}, ring.slicesByHash)
 
-	err = ring.insertSlice(17062952057979069181, "B")
+	err = ring.insertSlice(299, "B")
 	require.NoError(t, err)
 
 	require.Equal(t, map[uint64]uint64{
-		14180219187711517570: 1,
-		14420089009441877859: 1,
-		17062952057979069182: 17062952057979069181,
+		100: 1,
+		200: 1,
+		300: 299,
 	}, ring.slicesByHash)
 
 	err = ring.insertSlice(0, "B")
 	require.NoError(t, err)
 
 	require.Equal(t, map[uint64]uint64{
-		14180219187711517570: 1,
-		14420089009441877859: 1,
-		17062952057979069182: 17062952057979069181,
+		100: 1,
+		200: 1,
+		300: 299,
 	}, ring.slicesByHash)
 
-	ring.removeSlice(17062952057979069181)
+	ring.removeSlice(299)
 
 	require.Equal(t, map[uint64]uint64{
-		14180219187711517570: 1,
-		14420089009441877859: 1,
-		17062952057979069182: 1,
+		100: 1,
+		200: 1,
+		300: 1,
 	}, ring.slicesByHash)
 
 	// Test noop behavior.
-	ring.removeSlice(17062952057979069181)
+	ring.removeSlice(299)
 
-	err = ring.insertSlice(14420089009441877858, "B")
+	err = ring.insertSlice(199, "B")
 	require.NoError(t, err)
 
 	require.Equal(t, map[uint64]uint64{
-		14180219187711517570: 1,
-		14420089009441877859: 14420089009441877858,
-		17062952057979069182: 14420089009441877858,
+		100: 1,
+		200: 199,
+		300: 199,
 	}, ring.slicesByHash)
 
-	ring.removeSlice(14420089009441877858)
+	ring.removeSlice(199)
 
 	require.Equal(t, map[uint64]uint64{
-		14180219187711517570: 1,
-		14420089009441877859: 1,
-		17062952057979069182: 1,
+		100: 1,
+		200: 1,
+		300: 1,
 	}, ring.slicesByHash)
 
 	ring.removeSlice(1)
 
 	require.Equal(t, map[uint64]uint64{
-		14180219187711517570: 0,
-		14420089009441877859: 0,
-		17062952057979069182: 0,
+		100: 0,
+		200: 0,
+		300: 0,
 	}, ring.slicesByHash)
 
 	err = ring.insertSlice(1, "B")
 	require.NoError(t, err)
 
 	require.Equal(t, map[uint64]uint64{
-		14180219187711517570: 1,
-		14420089009441877859: 1,
-		17062952057979069182: 1,
+		100: 1,
+		200: 1,
+		300: 1,
 	}, ring.slicesByHash)
 
-	err = ring.insertSlice(14420089009441877859, "C")
+	err = ring.insertSlice(200, "C")
 	require.NoError(t, err)
 
 	require.Equal(t, map[uint64]uint64{
-		14180219187711517570: 1,
-		14420089009441877859: 14420089009441877859,
-		17062952057979069182: 14420089009441877859,
+		100: 1,
+		200: 200,
+		300: 200,
 	}, ring.slicesByHash)
 
-	err = ring.insertSlice(17062952057979069180, "C")
+	err = ring.insertSlice(298, "C")
 	require.NoError(t, err)
 
 	require.Equal(t, map[uint64]uint64{
-		14180219187711517570: 1,
-		14420089009441877859: 14420089009441877859,
-		17062952057979069182: 17062952057979069180,
+		100: 1,
+		200: 200,
+		300: 298,
 	}, ring.slicesByHash)
 
 	ring.Remove("1")
@@ -574,8 +635,8 @@ func TestKeyEmplacementAndRemovalAndSliceInsertionAndRemoval(t *testing.T) {
 	ring.Remove("1")
 
 	require.Equal(t, map[uint64]uint64{
-		14420089009441877859: 14420089009441877859,
-		17062952057979069182: 17062952057979069180,
+		200: 200,
+		300: 298,
 	}, ring.slicesByHash)
 }
 
@@ -630,6 +691,72 @@ func TestDeleteNode(t *testing.T) {
 	}, ring.vFactorByNode)
 }
 
+func TestDrainNode(t *testing.T) {
+	newRing := func() *Ring[RingPayloadType] {
+		ring, err := New(func(r *Ring[RingPayloadType]) {
+			r.BaseVFactor = 4
+			r.WatcherBuffer = 100
+		})
+		require.NoError(t, err)
+
+		for _, identifier := range []string{"A", "B", "C"} {
+			err = ring.CreateNode(Node{Identifier: identifier, VFactor: 1})
+			require.NoError(t, err)
+		}
+
+		for idx := 0; idx < 100; idx++ {
+			err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+			require.NoError(t, err)
+		}
+
+		return ring
+	}
+
+	expected := newRing()
+	expected.DeleteNode("A")
+
+	ring := newRing()
+	require.ErrorIs(t, ring.DrainNode("D"), ErrNodeNotFound)
+
+	owned := ring.Distribution()["A"]
+	c := ring.RegisterWatcher(Op[RingPayloadType]{Node: "A"})
+	stream := ring.RegisterEventStream()
+
+	events := make(chan []Event[RingPayloadType])
+	go func() {
+		var received []Event[RingPayloadType]
+		for event := range stream {
+			received = append(received, event)
+		}
+		events <- received
+	}()
+
+	err := ring.DrainNode("A")
+	require.NoError(t, err)
+	ring.DeregisterEventStream(stream)
+
+	require.Equal(t, expected.State(), ring.State())
+	require.False(t, ring.HasNode("A"))
+	require.Len(t, c, owned)
+
+	// Each slice is removed in turn, before the node itself.
+	received := <-events
+	slices := 0
+	for _, event := range received {
+		sliceEvent, ok := event.(SliceEvent)
+		if ok {
+			require.Equal(t, "A", sliceEvent.Node)
+			require.True(t, sliceEvent.Removed)
+			slices++
+		}
+	}
+	require.Equal(t, 4, slices)
+	require.Equal(t, NodeEvent{
+		Node:    Node{Identifier: "A", VFactor: 1},
+		Removed: true,
+	}, received[len(received)-1])
+}
+
 func TestUpdateNode(t *testing.T) {
 	ring, err := New(func(r *Ring[RingPayloadType]) {
 		r.BaseVFactor = 2
@@ -736,13 +863,29 @@ func TestListNodes(t *testing.T) {
 }
 
 func TestNodeCreationAndDeletionAndKeyEmplacementAndRemoval(t *testing.T) {
-	ring, err := New(func(r *Ring[RingPayloadType]) {
-		r.BaseVFactor = 2
+	for name, hash := range testHashes {
+		t.Run(name, func(t *testing.T) {
+			testNodeCreationAndDeletionAndKeyEmplacementAndRemoval(t, hash)
+		})
+	}
+}
+
+func testNodeCreationAndDeletionAndKeyEmplacementAndRemoval(t *testing.T, hash func(string) uint64) {
+	positions := map[string]uint64{
+		"A0": 10,
+		"A1": 250,
+		"B0": 50,
+		"B1": 400,
+	}
+	for key, position := range keyPositions {
+		positions[key] = position
+	}
 
+	ring := newTestRing(t, injectedHash(hash, positions), func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 2
 	})
-	require.NoError(t, err)
 
-	err = ring.CreateNode(Node{
+	err := ring.CreateNode(Node{
 		Identifier: "A",
 		VFactor:    1,
 	})
@@ -775,9 +918,9 @@ func TestNodeCreationAndDeletionAndKeyEmplacementAndRemoval(t *testing.T) {
 	require.Equal(
 		t,
 		map[uint64]uint64{
-			14180219187711517570: 2878424575911748999,
-			14420089009441877859: 2878424575911748999,
-			17062952057979069182: 15603869271526861367,
+			100: 10,
+			200: 10,
+			300: 250,
 		},
 		ring.slicesByHash,
 	)
@@ -789,9 +932,9 @@ func TestNodeCreationAndDeletionAndKeyEmplacementAndRemoval(t *testing.T) {
 	require.NoError(t, err)
 
 	require.Equal(t, map[uint64]uint64{
-		14180219187711517570: 5509762909502811065,
-		14420089009441877859: 5509762909502811065,
-		17062952057979069182: 15603869271526861367,
+		100: 50,
+		200: 50,
+		300: 250,
 	}, ring.slicesByHash)
 }
 
@@ -913,6 +1056,63 @@ func TestRingChangeNotificationWithNotificationFiltering(t *testing.T) {
 	}, <-c)
 }
 
+func TestRingChangeOrderingAcrossHashes(t *testing.T) {
+	positions := map[string]uint64{
+		"A0": 150,
+		"A1": 160,
+		"B0": 250,
+		"B1": 50,
+	}
+	for key, position := range keyPositions {
+		positions[key] = position
+	}
+
+	ring := newTestRing(t, injectedHash(MD5, positions), func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 2
+		r.Filter = func(Op[RingPayloadType]) string {
+			return ""
+		}
+	})
+
+	err := ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	for _, hashKey := range []string{"3", "1", "2"} {
+		for order, key := range []string{hashKey + "b", hashKey + "a"} {
+			err = ring.Emplace(&Key[RingPayloadType]{
+				InnerKey: &InnerKey{Key: key, Order: 1 - order},
+			}, hashKey)
+			require.NoError(t, err)
+		}
+	}
+
+	c := ring.RegisterWatcher(Op[RingPayloadType]{})
+
+	// The keys at 100 and 300 move to B, with 100 preceding 300 although B's slice at 250 converts 300 first.
+	moves := func(from, to string) []Op[RingPayloadType] {
+		var ops []Op[RingPayloadType]
+		for _, hashKey := range []string{"1", "3"} {
+			for _, key := range []string{hashKey + "a", hashKey + "b"} {
+				ops = append(ops, Op[RingPayloadType]{Key: key, Node: from, Removed: true, RingChange: true})
+			}
+			for _, key := range []string{hashKey + "a", hashKey + "b"} {
+				ops = append(ops, Op[RingPayloadType]{Key: key, Node: to, RingChange: true})
+			}
+		}
+		return ops
+	}
+
+	go func() {
+		err := ring.CreateNode(Node{Identifier: "B", VFactor: 1})
+		require.NoError(t, err)
+		ring.DeleteNode("B")
+	}()
+
+	for _, expected := range append(moves("A", "B"), moves("B", "A")...) {
+		require.Equal(t, expected, <-c)
+	}
+}
+
 func TestRingKeyOrdering(t *testing.T) {
 	ring, err := New(func(r *Ring[RingPayloadType]) {
 		r.BaseVFactor = 1
@@ -1077,7 +1277,34 @@ func TestRemoveIndex(t *testing.T) {
 }
 
 func TestFindKeyByNameEmptyKeyArray(t *testing.T) {
-	require.Equal(t, -1, findKeyByName(nil, ""))
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	require.Equal(t, -1, ring.findKeyByName(0, ""))
+}
+
+func TestFindKeyByName(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	for _, key := range []*InnerKey{{Key: "a", Order: 1}, {Key: "b", Order: 0}, {Key: "c", Order: 1}, {Key: "d", Order: 1}} {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: key}, "shared")
+		require.NoError(t, err)
+	}
+	hash := ring.HashKey("shared")
+
+	// Keys with the same order are sorted with the most recently emplaced first.
+	for idx, key := range []string{"b", "d", "c", "a"} {
+		require.Equal(t, idx, ring.findKeyByName(hash, key))
+	}
+	require.Equal(t, -1, ring.findKeyByName(hash, "e"))
+	require.Equal(t, -1, ring.findKeyByName(0, "a"))
+
+	ring.Remove("c")
+	require.Equal(t, map[uint64][]string{
+		hash: {"b", "d", "a"},
+	}, ring.OrderingReport())
+	require.Equal(t, 2, ring.findKeyByName(hash, "a"))
 }
 
 func TestInsertSliceAlreadyExists(t *testing.T) {
@@ -1092,9 +1319,11 @@ func TestCreateNodeAlreadyExists(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, ring.CreateNode(Node{
 		Identifier: "A",
+		VFactor:    1,
 	}))
 	require.Equal(t, ErrNodeAlreadyExists, ring.CreateNode(Node{
 		Identifier: "A",
+		VFactor:    1,
 	}))
 }
 
@@ -1219,3 +1448,3200 @@ func TestKeyUpdates(t *testing.T) {
 
 	<-done
 }
+
+func TestIsEmpty(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+	require.True(t, ring.IsEmpty())
+
+	err = ring.CreateNode(Node{
+		Identifier: "A",
+		VFactor:    1,
+	})
+	require.NoError(t, err)
+	require.False(t, ring.IsEmpty())
+
+	ring.DeleteNode("A")
+	require.True(t, ring.IsEmpty())
+}
+
+func TestRewatch(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{
+		Identifier: "A",
+		VFactor:    1,
+	})
+	require.NoError(t, err)
+
+	c := ring.RegisterWatcher(Op[RingPayloadType]{
+		Node: "A",
+	})
+
+	require.Equal(t, ErrWatcherNotFound, ring.Rewatch(Op[RingPayloadType]{
+		Node: "C",
+	}, Op[RingPayloadType]{
+		Node: "B",
+	}))
+
+	err = ring.Rewatch(Op[RingPayloadType]{
+		Node: "A",
+	}, Op[RingPayloadType]{
+		Node: "B",
+	})
+	require.NoError(t, err)
+
+	ring.RegisterWatcher(Op[RingPayloadType]{
+		Node: "A",
+	})
+
+	require.Equal(t, ErrWatcherAlreadyExists, ring.Rewatch(Op[RingPayloadType]{
+		Node: "B",
+	}, Op[RingPayloadType]{
+		Node: "A",
+	}))
+
+	ring.DeregisterWatcher(Op[RingPayloadType]{
+		Node: "A",
+	})
+
+	err = ring.CreateNode(Node{
+		Identifier: "B",
+		VFactor:    1,
+	})
+	require.NoError(t, err)
+
+	ring.DeleteNode("A")
+
+	go func() {
+		err := ring.Emplace(&Key[RingPayloadType]{
+			InnerKey: &InnerKey{
+				Key: "1",
+			},
+		})
+		require.NoError(t, err)
+	}()
+
+	require.Equal(t, Op[RingPayloadType]{
+		Key:  "1",
+		Node: "B",
+	}, <-c)
+}
+
+func TestEmplaceColocated(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{
+		Identifier: "A",
+		VFactor:    4,
+	})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{
+		Identifier: "B",
+		VFactor:    4,
+	})
+	require.NoError(t, err)
+
+	err = ring.EmplaceColocated("group", []*Key[RingPayloadType]{
+		{InnerKey: &InnerKey{Key: "key-2", Order: 2}},
+		{InnerKey: &InnerKey{Key: "key-1", Order: 1}},
+		{InnerKey: &InnerKey{Key: "key-3", Order: 3}},
+	})
+	require.NoError(t, err)
+
+	hash := ring.Hash("group")
+	require.Equal(t, map[string]uint64{
+		"key-1": hash,
+		"key-2": hash,
+		"key-3": hash,
+	}, ring.hashesByKey)
+
+	require.Equal(t, []*InnerKey{
+		{Key: "key-1", Order: 1},
+		{Key: "key-2", Order: 2},
+		{Key: "key-3", Order: 3},
+	}, ring.keysByHash[hash])
+}
+
+func TestEmplaceColocatedValidatesUpFront(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "existing"}})
+	require.NoError(t, err)
+
+	err = ring.EmplaceColocated("group", []*Key[RingPayloadType]{
+		{InnerKey: &InnerKey{Key: "new"}},
+		{InnerKey: &InnerKey{Key: "existing"}},
+	})
+	require.Equal(t, ErrKeyAlreadyExists, err)
+
+	err = ring.EmplaceColocated("group", []*Key[RingPayloadType]{
+		{InnerKey: &InnerKey{Key: "new"}},
+		{InnerKey: &InnerKey{Key: "new"}},
+	})
+	require.Equal(t, ErrKeyAlreadyExists, err)
+
+	err = ring.EmplaceColocated("group", []*Key[RingPayloadType]{nil})
+	require.Equal(t, ErrNilKey, err)
+
+	require.Equal(t, 1, len(ring.hashesByKey))
+	require.Equal(t, 1, len(ring.contentByKey))
+}
+
+func TestNodeShare(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	_, err = ring.NodeShare("A")
+	require.Equal(t, ErrNodeNotFound, err)
+
+	err = ring.insertSlice(0, "A")
+	require.NoError(t, err)
+	ring.vFactorByNode["A"] = 1
+
+	share, err := ring.NodeShare("A")
+	require.NoError(t, err)
+	require.Equal(t, 1.0, share)
+
+	err = ring.insertSlice(1<<62, "B")
+	require.NoError(t, err)
+	ring.vFactorByNode["B"] = 1
+
+	share, err = ring.NodeShare("A")
+	require.NoError(t, err)
+	require.Equal(t, 0.25, share)
+
+	share, err = ring.NodeShare("B")
+	require.NoError(t, err)
+	require.Equal(t, 0.75, share)
+}
+
+func TestFindBySecondary(t *testing.T) {
+	type payload struct {
+		Tenant string
+	}
+
+	ring, err := New(func(r *Ring[payload]) {
+		r.IndexBy = func(p payload) string {
+			return p.Tenant
+		}
+	})
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[payload]{
+		InnerKey: &InnerKey{Key: "1"},
+		Value:    payload{Tenant: "x"},
+	})
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[payload]{
+		InnerKey: &InnerKey{Key: "2"},
+		Value:    payload{Tenant: "x"},
+	})
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[payload]{
+		InnerKey: &InnerKey{Key: "3"},
+		Value:    payload{Tenant: "y"},
+	})
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{"1", "2"}, ring.FindBySecondary("x"))
+	require.ElementsMatch(t, []string{"3"}, ring.FindBySecondary("y"))
+
+	err = ring.Update(&Key[payload]{
+		InnerKey: &InnerKey{Key: "2"},
+		Value:    payload{Tenant: "y"},
+	})
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{"1"}, ring.FindBySecondary("x"))
+	require.ElementsMatch(t, []string{"2", "3"}, ring.FindBySecondary("y"))
+
+	ring.Remove("1")
+
+	require.Empty(t, ring.FindBySecondary("x"))
+	require.Equal(t, 1, len(ring.keysByIndex))
+}
+
+func TestFindBySecondaryWithoutIndex(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+
+	require.Empty(t, ring.FindBySecondary(""))
+	require.Equal(t, 0, len(ring.keysByIndex))
+}
+
+func TestDrainOrphans(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	require.Empty(t, ring.DrainOrphans())
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "2", Order: 1}}, "1")
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "3"}})
+	require.NoError(t, err)
+
+	c := ring.RegisterWatcher(Op[RingPayloadType]{})
+
+	done := make(chan []string)
+	go func() {
+		done <- ring.DrainOrphans()
+	}()
+
+	for _, key := range []string{"1", "2", "3"} {
+		require.Equal(t, Op[RingPayloadType]{
+			Key:     key,
+			Removed: true,
+		}, <-c)
+	}
+
+	require.Equal(t, []string{"1", "2", "3"}, <-done)
+	require.Equal(t, 0, len(ring.empty))
+	require.Equal(t, 0, len(ring.hashes))
+	require.Equal(t, 0, len(ring.hashesByKey))
+	require.Equal(t, 0, len(ring.contentByKey))
+}
+
+func TestRestorePayload(t *testing.T) {
+	ring, err := New(func(r *Ring[int]) {
+		r.RetainRemovedPayloads = 2
+	})
+	require.NoError(t, err)
+
+	for idx, key := range []string{"1", "2", "3"} {
+		err = ring.Emplace(&Key[int]{
+			InnerKey: &InnerKey{Key: key},
+			Value:    idx + 10,
+		})
+		require.NoError(t, err)
+	}
+
+	_, ok := ring.RestorePayload("1")
+	require.False(t, ok)
+
+	ring.Remove("1")
+	ring.Remove("2")
+	ring.Remove("3")
+
+	// Only the two most recent removals are retained.
+	_, ok = ring.RestorePayload("1")
+	require.False(t, ok)
+
+	payload, ok := ring.RestorePayload("2")
+	require.True(t, ok)
+	require.Equal(t, 11, payload)
+
+	payload, ok = ring.RestorePayload("3")
+	require.True(t, ok)
+	require.Equal(t, 12, payload)
+}
+
+func TestRestorePayloadDisabled(t *testing.T) {
+	ring, err := New[int]()
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[int]{
+		InnerKey: &InnerKey{Key: "1"},
+		Value:    1,
+	})
+	require.NoError(t, err)
+
+	ring.Remove("1")
+
+	_, ok := ring.RestorePayload("1")
+	require.False(t, ok)
+	require.Nil(t, ring.retained)
+}
+
+func BenchmarkEmplaceParallel(b *testing.B) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(b, err)
+
+	for idx := 0; idx < 8; idx++ {
+		err = ring.CreateNode(Node{
+			Identifier: strconv.Itoa(idx),
+			VFactor:    100,
+		})
+		require.NoError(b, err)
+	}
+
+	var next atomic.Int64
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			err := ring.Emplace(&Key[RingPayloadType]{
+				InnerKey: &InnerKey{
+					Key: strconv.FormatInt(next.Add(1), 10),
+				},
+			})
+			if err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
+
+func TestAssertOwner(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	require.Equal(t, ErrKeyNotFound, ring.AssertOwner("1", "A"))
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+
+	require.NoError(t, ring.AssertOwner("1", ""))
+
+	err = ring.insertSlice(0, "A")
+	require.NoError(t, err)
+
+	require.NoError(t, ring.AssertOwner("1", "A"))
+
+	err = ring.AssertOwner("1", "B")
+	require.ErrorIs(t, err, ErrUnexpectedOwner)
+	require.Contains(t, err.Error(), `owned by node "A", expected node "B"`)
+}
+
+func TestOrderedHelpersWithOtherHashTypes(t *testing.T) {
+	var hashes []string
+
+	for _, hash := range []string{"c", "a", "b"} {
+		hashes, _ = insertPreserveOrder(hashes, hash, findIndex)
+	}
+	require.Equal(t, []string{"a", "b", "c"}, hashes)
+
+	require.Equal(t, 1, findIndex(hashes, "b"))
+	require.Equal(t, 2, findPrevIndex(hashes, 0))
+	require.Equal(t, 0, findNextIndex(hashes, 2))
+
+	hashes, err := removeIndex(hashes, findIndex(hashes, "b"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "c"}, hashes)
+}
+
+func TestKeyPositionsForNode(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	_, _, err = ring.KeyPositionsForNode("A")
+	require.Equal(t, ErrNodeNotFound, err)
+
+	err = ring.CreateNode(Node{
+		Identifier: "A",
+		VFactor:    1,
+	})
+	require.NoError(t, err)
+
+	positions, keys, err := ring.KeyPositionsForNode("A")
+	require.NoError(t, err)
+	require.Equal(t, 0, positions)
+	require.Equal(t, 0, keys)
+
+	err = ring.EmplaceColocated("group", []*Key[RingPayloadType]{
+		{InnerKey: &InnerKey{Key: "1"}},
+		{InnerKey: &InnerKey{Key: "2"}},
+		{InnerKey: &InnerKey{Key: "3"}},
+	})
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "4"}})
+	require.NoError(t, err)
+
+	positions, keys, err = ring.KeyPositionsForNode("A")
+	require.NoError(t, err)
+	require.Equal(t, 2, positions)
+	require.Equal(t, 4, keys)
+}
+
+func TestNodeSnapshot(t *testing.T) {
+	ring, err := New[int]()
+	require.NoError(t, err)
+
+	_, err = ring.NodeSnapshot("A")
+	require.Equal(t, ErrNodeNotFound, err)
+
+	err = ring.insertSlice(0, "A")
+	require.NoError(t, err)
+	ring.vFactorByNode["A"] = 1
+
+	err = ring.insertSlice(1<<63, "B")
+	require.NoError(t, err)
+	ring.vFactorByNode["B"] = 1
+
+	// Keys hashing into the lower half of the ring belong to A.
+	ring.Hash = func(s string) uint64 {
+		if s == "low" {
+			return 1
+		}
+		return 1<<63 + 1
+	}
+
+	err = ring.Emplace(&Key[int]{InnerKey: &InnerKey{Key: "1", Order: 1}, Value: 1}, "low")
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[int]{InnerKey: &InnerKey{Key: "2", Order: 0}, Value: 2}, "low")
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[int]{InnerKey: &InnerKey{Key: "3"}, Value: 3}, "high")
+	require.NoError(t, err)
+
+	ops, err := ring.NodeSnapshot("A")
+	require.NoError(t, err)
+	require.Equal(t, []Op[int]{
+		{Key: "2", Node: "A", Payload: 2},
+		{Key: "1", Node: "A", Payload: 1},
+	}, ops)
+
+	ops, err = ring.NodeSnapshot("B")
+	require.NoError(t, err)
+	require.Equal(t, []Op[int]{
+		{Key: "3", Node: "B", Payload: 3},
+	}, ops)
+}
+
+func TestCountWeightedByRecency(t *testing.T) {
+	clock := newFakeClock()
+
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.Clock = clock
+	})
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "orphan"}})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{
+		Identifier: "A",
+		VFactor:    1,
+	})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{
+		Identifier: "B",
+		VFactor:    1,
+	})
+	require.NoError(t, err)
+
+	ring.DeleteNode("A")
+	ring.DeleteNode("B")
+
+	require.Equal(t, map[string]float64{
+		"": 1,
+	}, ring.CountWeightedByRecency(time.Minute))
+
+	err = ring.CreateNode(Node{
+		Identifier: "A",
+		VFactor:    1,
+	})
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "fresh"}})
+	require.NoError(t, err)
+
+	clock.Advance(time.Minute)
+
+	// Touching a key resets its age, while an untouched key ages from its emplacement.
+	ring.Touch("fresh")
+	ring.Touch("missing")
+
+	clock.Advance(time.Minute)
+
+	require.Equal(t, map[string]float64{
+		"A": 0.5 + 0.25,
+	}, ring.CountWeightedByRecency(time.Minute))
+
+	require.Equal(t, map[string]float64{
+		"A": 2,
+	}, ring.CountWeightedByRecency(0))
+
+	ring.Remove("orphan")
+	require.Equal(t, 1, len(ring.touchedByKey))
+}
+
+func TestResizeNodesCollision(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	// "B10" is the name of both the first slice of node "B1" and the eleventh slice of node "B".
+	for _, identifier := range []string{"A", "B", "B1"} {
+		err = ring.CreateNode(Node{Identifier: identifier, VFactor: 1})
+		require.NoError(t, err)
+	}
+	for idx := 0; idx < 100; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+		require.NoError(t, err)
+	}
+
+	state, topology := ring.State(), ring.TopologyState()
+
+	ring.WatcherBuffer = 200
+	c := ring.WatchAll()
+	stream := ring.RegisterEventStream()
+	events := make(chan []Event[RingPayloadType])
+	go func() {
+		var received []Event[RingPayloadType]
+		for event := range stream {
+			received = append(received, event)
+		}
+		events <- received
+	}()
+
+	// "A" is resized first, but the collision of "B" leaves it untouched.
+	_, err = ring.ResizeNodes(map[string]int{"A": 4, "B": 11})
+	require.ErrorIs(t, err, ErrSliceHashCollision)
+
+	ring.DeregisterEventStream(stream)
+	require.Empty(t, <-events)
+	require.Empty(t, c)
+
+	require.Equal(t, state, ring.State())
+	require.Equal(t, topology, ring.TopologyState())
+	node, err := ring.GetNode("A")
+	require.NoError(t, err)
+	require.Equal(t, 1, node.VFactor)
+}
+
+func TestResizeNodes(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.Filter = func(o Op[RingPayloadType]) string {
+			return "all"
+		}
+	})
+	require.NoError(t, err)
+
+	for _, identifier := range []string{"A", "B", "C"} {
+		err = ring.CreateNode(Node{
+			Identifier: identifier,
+			VFactor:    2,
+		})
+		require.NoError(t, err)
+	}
+
+	for idx := 0; idx < 200; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+		require.NoError(t, err)
+	}
+
+	before := make(map[string]string)
+	for key := range ring.hashesByKey {
+		before[key], _ = ring.nodeForKey(key)
+	}
+
+	_, err = ring.ResizeNodes(map[string]int{
+		"A": 5,
+		"D": 1,
+	})
+	require.Equal(t, ErrNodeNotFound, err)
+	require.Equal(t, 2, ring.vFactorByNode["A"])
+
+	c := ring.RegisterWatcher(Op[RingPayloadType]{})
+	ops := make(chan []Op[RingPayloadType])
+	go func() {
+		var received []Op[RingPayloadType]
+		for op := range c {
+			received = append(received, op)
+		}
+		ops <- received
+	}()
+
+	moved, err := ring.ResizeNodes(map[string]int{
+		"A": 6,
+		"B": 1,
+	})
+	require.NoError(t, err)
+
+	ring.DeregisterWatcher(Op[RingPayloadType]{})
+	received := <-ops
+
+	expected := make(map[string]int)
+	for key, prev := range before {
+		node, _ := ring.nodeForKey(key)
+		if node != prev {
+			expected[key] = 2
+		}
+	}
+
+	// Every moved key is removed from its original owner and added to its final owner exactly once.
+	counts := make(map[string]int)
+	for _, op := range received {
+		require.True(t, op.RingChange)
+		if op.Removed {
+			require.Equal(t, before[op.Key], op.Node)
+		} else {
+			require.NoError(t, ring.AssertOwner(op.Key, op.Node))
+		}
+		counts[op.Key]++
+	}
+
+	require.Equal(t, expected, counts)
+	require.NotZero(t, moved)
+	require.Equal(t, len(expected), moved)
+	require.Equal(t, map[string]int{
+		"A": 6,
+		"B": 1,
+		"C": 2,
+	}, ring.vFactorByNode)
+}
+
+func TestTopologyStateRoundTrip(t *testing.T) {
+	source, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = source.CreateNode(Node{
+		Identifier: "A",
+		VFactor:    3,
+	})
+	require.NoError(t, err)
+
+	err = source.CreateNode(Node{
+		Identifier: "B",
+		VFactor:    2,
+	})
+	require.NoError(t, err)
+
+	err = source.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(source.TopologyState())
+	require.NoError(t, err)
+
+	topology := &TopologyState{}
+	require.NoError(t, json.Unmarshal(data, topology))
+
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	// Keys parked before the topology is loaded are placed onto it.
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+
+	require.NoError(t, ring.LoadTopology(topology))
+	require.Equal(t, source.TopologyState(), ring.TopologyState())
+	require.Equal(t, source.slicesByHash, ring.slicesByHash)
+	require.Equal(t, 0, len(ring.empty))
+
+	require.Equal(t, ErrTopologyAlreadyExists, ring.LoadTopology(topology))
+}
+
+func TestLoadTopologyInconsistent(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	require.Equal(t, ErrInconsistentState, ring.LoadTopology(&TopologyState{
+		Slices:        []uint64{1},
+		NodesBySlice:  map[uint64]string{2: "A"},
+		VFactorByNode: map[string]int{"A": 1},
+	}))
+
+	require.Equal(t, ErrInconsistentState, ring.LoadTopology(&TopologyState{
+		Slices:        []uint64{1},
+		NodesBySlice:  map[uint64]string{1: "A"},
+		VFactorByNode: map[string]int{"B": 1},
+	}))
+
+	require.Equal(t, ErrInconsistentState, ring.LoadTopology(&TopologyState{
+		Slices:        []uint64{1, 1},
+		NodesBySlice:  map[uint64]string{1: "A", 2: "A"},
+		VFactorByNode: map[string]int{"A": 2},
+	}))
+
+	require.Equal(t, 0, len(ring.slices))
+	require.Equal(t, 0, len(ring.vFactorByNode))
+}
+
+func TestUnwatchedKeys(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.insertSlice(0, "A")
+	require.NoError(t, err)
+
+	err = ring.insertSlice(1<<63, "B")
+	require.NoError(t, err)
+
+	ring.Hash = func(s string) uint64 {
+		if s == "1" || s == "2" {
+			return 1
+		}
+		return 1<<63 + 1
+	}
+
+	for _, key := range []string{"1", "2", "3"} {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: key}})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []string{"1", "2", "3"}, ring.UnwatchedKeys())
+
+	ring.RegisterWatcher(Op[RingPayloadType]{
+		Node: "A",
+	})
+
+	require.Equal(t, []string{"3"}, ring.UnwatchedKeys())
+
+	ring.RegisterWatcher(Op[RingPayloadType]{
+		Node: "B",
+	})
+
+	require.Empty(t, ring.UnwatchedKeys())
+}
+
+func TestUnwatchedKeysWatchAll(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	for _, key := range []string{"1", "2"} {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: key}})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []string{"1", "2"}, ring.UnwatchedKeys())
+
+	// A channel provided by WatchAll observes every key.
+	all := ring.WatchAll()
+	require.Empty(t, ring.UnwatchedKeys())
+
+	ring.UnwatchAll(all)
+	require.Equal(t, []string{"1", "2"}, ring.UnwatchedKeys())
+}
+
+func TestCompareAndUpdate(t *testing.T) {
+	ring, err := New(func(r *Ring[int]) {
+		r.Filter = func(o Op[int]) string {
+			return fmt.Sprintf("%s%t", o.Node, o.Updated)
+		}
+	})
+	require.NoError(t, err)
+
+	eq := func(a, b int) bool { return a == b }
+
+	require.Equal(t, ErrKeyNotFound, ring.CompareAndUpdate("1", 0, 1, eq))
+
+	err = ring.CreateNode(Node{
+		Identifier: "A",
+		VFactor:    1,
+	})
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[int]{InnerKey: &InnerKey{Key: "1"}, Value: 1})
+	require.NoError(t, err)
+
+	c := ring.RegisterWatcher(Op[int]{
+		Node:    "A",
+		Updated: true,
+	})
+
+	// A mismatch neither updates nor notifies, so this does not block.
+	require.Equal(t, ErrCASMismatch, ring.CompareAndUpdate("1", 2, 3, eq))
+	require.Equal(t, 1, ring.contentByKey["1"])
+
+	go func() {
+		require.NoError(t, ring.CompareAndUpdate("1", 1, 3, eq))
+	}()
+
+	require.Equal(t, Op[int]{
+		Key:     "1",
+		Node:    "A",
+		Payload: 3,
+		Updated: true,
+	}, <-c)
+}
+
+func TestColdSlices(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	require.Empty(t, ring.ColdSlices())
+
+	ring.Hash = func(s string) uint64 {
+		return 15
+	}
+
+	for _, slice := range []uint64{0, 10, 20} {
+		err = ring.insertSlice(slice, "A")
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []uint64{0, 10, 20}, ring.ColdSlices())
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+
+	require.Equal(t, []uint64{0, 20}, ring.ColdSlices())
+}
+
+func TestConvertProgress(t *testing.T) {
+	type progress struct {
+		done  int
+		total int
+	}
+
+	var reports []progress
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.OnConvertProgress = func(done, total int) {
+			reports = append(reports, progress{done: done, total: total})
+		}
+	})
+	require.NoError(t, err)
+
+	err = ring.insertSlice(0, "A")
+	require.NoError(t, err)
+
+	ring.hashes = make([]uint64, convertProgressInterval+1)
+	for idx := range ring.hashes {
+		ring.hashes[idx] = uint64(idx + 3)
+		ring.slicesByHash[uint64(idx+3)] = 0
+	}
+
+	// Take every hash from the lone slice.
+	err = ring.insertSlice(2, "B")
+	require.NoError(t, err)
+
+	require.Equal(t, []progress{
+		{done: convertProgressInterval, total: convertProgressInterval + 1},
+		{done: convertProgressInterval + 1, total: convertProgressInterval + 1},
+	}, reports)
+
+	// Remove the slice, converting the same range back.
+	reports = nil
+	ring.removeSlice(2)
+
+	require.Equal(t, []progress{
+		{done: convertProgressInterval, total: convertProgressInterval + 1},
+		{done: convertProgressInterval + 1, total: convertProgressInterval + 1},
+	}, reports)
+}
+
+func TestConvertProgressAcrossSlices(t *testing.T) {
+	type progress struct {
+		done  int
+		total int
+	}
+
+	var reports []progress
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.OnConvertProgress = func(done, total int) {
+			reports = append(reports, progress{done: done, total: total})
+		}
+	})
+	require.NoError(t, err)
+
+	for _, node := range []string{"A", "B"} {
+		err = ring.CreateNode(Node{Identifier: node, VFactor: 4})
+		require.NoError(t, err)
+	}
+
+	for idx := 0; idx < 4*convertProgressInterval; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+		require.NoError(t, err)
+	}
+
+	// Progress runs across every slice of the change, ending once every hash is reassigned.
+	requireMonotonic := func(change func()) {
+		reports = nil
+		change()
+
+		require.NotEmpty(t, reports)
+		last := reports[len(reports)-1]
+		require.Equal(t, last.total, last.done)
+		for idx, report := range reports {
+			require.Equal(t, last.total, report.total)
+			if idx > 0 {
+				require.Greater(t, report.done, reports[idx-1].done)
+			}
+		}
+	}
+
+	requireMonotonic(func() {
+		err = ring.CreateNode(Node{Identifier: "C", VFactor: 8})
+		require.NoError(t, err)
+	})
+	require.Greater(t, reports[len(reports)-1].total, convertProgressInterval)
+
+	requireMonotonic(func() {
+		_, err = ring.ResizeNodes(map[string]int{"A": 1, "B": 8})
+		require.NoError(t, err)
+	})
+
+	requireMonotonic(func() {
+		ring.DeleteNode("C")
+	})
+}
+
+func TestNewWithNodes(t *testing.T) {
+	ring, err := NewWithNodes([]Node{
+		{Identifier: "A", VFactor: 1},
+		{Identifier: "B", VFactor: 2},
+	}, func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 2
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]int{
+		"A": 1,
+		"B": 2,
+	}, ring.vFactorByNode)
+	require.Equal(t, 6, len(ring.slices))
+
+	_, err = NewWithNodes([]Node{
+		{Identifier: "A", VFactor: 1},
+		{Identifier: "A", VFactor: 1},
+	}, func(r *Ring[RingPayloadType]) {})
+	require.Equal(t, ErrNodeAlreadyExists, err)
+
+	_, err = NewWithNodes[RingPayloadType](nil, func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 0
+	})
+	require.Equal(t, ErrInvalidBaseVFactor, err)
+}
+
+func TestDetectSliceCollisions(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.Hash = func(s string) uint64 {
+			switch s {
+			case "A0", "B1":
+				return 1
+			case "A1", "A2":
+				return 2
+			}
+			return 3
+		}
+	})
+	require.NoError(t, err)
+
+	// Nodes can no longer be created with colliding slices, so the collisions are set up directly,
+	// as they would be by loading an inconsistent topology.
+	ring.vFactorByNode["A"] = 3
+	require.NoError(t, ring.insertSlice(1, "A"))
+	require.NoError(t, ring.insertSlice(2, "A"))
+
+	require.Equal(t, map[uint64][]string{
+		2: {"A"},
+	}, ring.DetectSliceCollisions())
+
+	ring.vFactorByNode["B"] = 2
+	require.NoError(t, ring.insertSlice(3, "B"))
+
+	require.Equal(t, map[uint64][]string{
+		1: {"B"},
+		2: {"A"},
+	}, ring.DetectSliceCollisions())
+}
+
+func TestCreateNodeSliceHashCollision(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.ToSliceName = func(s string, i int) string {
+			if s == "B" && i == 1 {
+				return "A0"
+			}
+			return fmt.Sprintf("%s%d", s, i)
+		}
+	})
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	state := ring.State()
+
+	// The second slice of B collides with the first slice of A, so none of the slices of B are inserted.
+	err = ring.CreateNode(Node{Identifier: "B", VFactor: 2})
+	require.ErrorIs(t, err, ErrSliceHashCollision)
+
+	_, err = ring.GetNode("B")
+	require.ErrorIs(t, err, ErrNodeNotFound)
+	require.Equal(t, state, ring.State())
+	require.Empty(t, ring.DetectSliceCollisions())
+
+	// Slices of the same node colliding with each other are also rejected.
+	err = ring.CreateNode(Node{Identifier: "C", VFactor: 2})
+	require.NoError(t, err)
+
+	ring.ToSliceName = func(s string, i int) string { return s }
+	err = ring.CreateNode(Node{Identifier: "D", VFactor: 2})
+	require.ErrorIs(t, err, ErrSliceHashCollision)
+	require.ElementsMatch(t, []string{"A", "C"}, ring.ListNodes())
+	require.Len(t, ring.slices, 3)
+}
+
+func TestUpdateNodeSliceHashCollision(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 1
+		r.ToSliceName = func(s string, i int) string {
+			if s == "A" && i == 3 {
+				return "B0"
+			}
+			return fmt.Sprintf("%s%d", s, i)
+		}
+	})
+	require.NoError(t, err)
+
+	for _, identifier := range []string{"A", "B"} {
+		err = ring.CreateNode(Node{Identifier: identifier, VFactor: 1})
+		require.NoError(t, err)
+	}
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+
+	state := ring.State()
+	stream := ring.RegisterEventStream()
+
+	// The third of the three new slices of A collides with the first slice of B, so none are inserted.
+	err = ring.UpdateNode(Node{Identifier: "A", VFactor: 4})
+	require.ErrorIs(t, err, ErrSliceHashCollision)
+
+	ring.DeregisterEventStream(stream)
+	_, ok := <-stream
+	require.False(t, ok)
+
+	node, err := ring.GetNode("A")
+	require.NoError(t, err)
+	require.Equal(t, 1, node.VFactor)
+	require.Equal(t, []uint64{ring.HashSlice("A", 0)}, ring.ListSlicesForNode("A"))
+	require.Equal(t, state, ring.State())
+}
+
+func TestHashKeyAndHashSlice(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.ToSliceName = func(s string, i int) string {
+			return fmt.Sprintf("%s-%d", s, i)
+		}
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, MD5("key"), ring.HashKey("key"))
+	require.Equal(t, MD5("A-1"), ring.HashSlice("A", 1))
+
+	err = ring.CreateNode(Node{
+		Identifier: "A",
+		VFactor:    2,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "A", ring.nodesBySlice[ring.HashSlice("A", 0)])
+	require.Equal(t, "A", ring.nodesBySlice[ring.HashSlice("A", 1)])
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}}, "key")
+	require.NoError(t, err)
+
+	require.Equal(t, ring.HashKey("key"), ring.hashesByKey["1"])
+}
+
+// steppingClock is a fake clock which advances by a millisecond each time it is read.
+type steppingClock struct {
+	*fakeClock
+}
+
+func (clock steppingClock) Now() time.Time {
+	clock.Advance(time.Millisecond)
+	return clock.fakeClock.Now()
+}
+
+func TestOnOpTiming(t *testing.T) {
+	type timing struct {
+		op  string
+		dur time.Duration
+	}
+
+	var timings []timing
+	var ring *Ring[RingPayloadType]
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.Clock = steppingClock{newFakeClock()}
+		r.OnOpTiming = func(op string, dur time.Duration) {
+			// The ring is unlocked by the time the callback is invoked.
+			ring.IsEmpty()
+			timings = append(timings, timing{op: op, dur: dur})
+		}
+	})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{
+		Identifier: "A",
+		VFactor:    1,
+	})
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{
+		InnerKey: &InnerKey{
+			Key: "1",
+		},
+	})
+	require.NoError(t, err)
+
+	ring.Remove("1")
+	ring.DeleteNode("A")
+
+	// Emplace reads the clock once more to record when the key was touched.
+	require.Equal(t, []timing{
+		{op: "CreateNode", dur: time.Millisecond},
+		{op: "Emplace", dur: 2 * time.Millisecond},
+		{op: "Remove", dur: time.Millisecond},
+		{op: "DeleteNode", dur: time.Millisecond},
+	}, timings)
+}
+
+func TestZones(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	for _, node := range []Node{
+		{Identifier: "A", VFactor: 1, Zone: "east"},
+		{Identifier: "B", VFactor: 1, Zone: "west"},
+		{Identifier: "C", VFactor: 1, Zone: "east"},
+		{Identifier: "D", VFactor: 1},
+	} {
+		err = ring.CreateNode(node)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []string{"A", "C"}, ring.NodesInZone("east"))
+	require.Equal(t, []string{"B"}, ring.NodesInZone("west"))
+	require.Empty(t, ring.NodesInZone("north"))
+	require.Empty(t, ring.NodesInZone(""))
+
+	colocated, err := ring.Colocated("A")
+	require.NoError(t, err)
+	require.Equal(t, []string{"C"}, colocated)
+
+	colocated, err = ring.Colocated("B")
+	require.NoError(t, err)
+	require.Empty(t, colocated)
+
+	colocated, err = ring.Colocated("D")
+	require.NoError(t, err)
+	require.Empty(t, colocated)
+
+	_, err = ring.Colocated("E")
+	require.ErrorIs(t, err, ErrNodeNotFound)
+
+	node, err := ring.GetNode("C")
+	require.NoError(t, err)
+	require.Equal(t, Node{Identifier: "C", VFactor: 1, Zone: "east"}, node)
+
+	ring.DeleteNode("C")
+	require.Equal(t, []string{"A"}, ring.NodesInZone("east"))
+
+	// Zones are carried by the topology.
+	loaded, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = loaded.LoadTopology(ring.TopologyState())
+	require.NoError(t, err)
+	require.Equal(t, []string{"A"}, loaded.NodesInZone("east"))
+}
+
+func TestOrderingReport(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	for _, key := range []*InnerKey{
+		{Key: "c", Order: 2},
+		{Key: "a", Order: 0},
+		{Key: "b", Order: 1},
+		{Key: "d", Order: 0},
+	} {
+		hk := "shared"
+		if key.Key == "d" {
+			hk = "alone"
+		}
+
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: key}, hk)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, map[uint64][]string{
+		ring.HashKey("shared"): {"a", "b", "c"},
+	}, ring.OrderingReport())
+}
+
+func TestInvalidVFactor(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{
+		Identifier: "A",
+	})
+	require.ErrorIs(t, err, ErrInvalidVFactor)
+	require.Empty(t, ring.ListNodes())
+
+	err = ring.CreateNode(Node{
+		Identifier: "A",
+		VFactor:    1,
+	})
+	require.NoError(t, err)
+
+	err = ring.UpdateNode(Node{
+		Identifier: "A",
+		VFactor:    0,
+	})
+	require.ErrorIs(t, err, ErrInvalidVFactor)
+
+	_, err = ring.ResizeNodes(map[string]int{"A": -1})
+	require.ErrorIs(t, err, ErrInvalidVFactor)
+
+	node, err := ring.GetNode("A")
+	require.NoError(t, err)
+	require.Equal(t, 1, node.VFactor)
+	require.Len(t, ring.slices, 1)
+
+	loaded, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = loaded.LoadTopology(&TopologyState{
+		VFactorByNode: map[string]int{"A": 0},
+	})
+	require.ErrorIs(t, err, ErrInvalidVFactor)
+}
+
+func TestNegativeVFactor(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: -1})
+	require.ErrorIs(t, err, ErrInvalidVFactor)
+
+	// No partial state remains, so the node can be created afterwards.
+	require.Empty(t, ring.vFactorByNode)
+	require.Empty(t, ring.slices)
+	require.False(t, ring.HasNode("A"))
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 2})
+	require.NoError(t, err)
+
+	err = ring.UpdateNode(Node{Identifier: "A", VFactor: -3})
+	require.ErrorIs(t, err, ErrInvalidVFactor)
+	require.Equal(t, map[string]int{"A": 2}, ring.vFactorByNode)
+	require.Len(t, ring.slices, 2)
+
+	// The VFactor is validated before the node is found.
+	err = ring.UpdateNode(Node{Identifier: "B", VFactor: -1})
+	require.ErrorIs(t, err, ErrInvalidVFactor)
+}
+
+func TestTopologyHash(t *testing.T) {
+	build := func(nodes ...string) *Ring[RingPayloadType] {
+		ring, err := New(func(r *Ring[RingPayloadType]) {
+			r.BaseVFactor = 2
+		})
+		require.NoError(t, err)
+
+		for _, node := range nodes {
+			err = ring.CreateNode(Node{Identifier: node, VFactor: 1})
+			require.NoError(t, err)
+		}
+
+		return ring
+	}
+
+	ring := build("A", "B", "C")
+	fingerprint := ring.TopologyHash()
+
+	// Insertion order does not matter.
+	require.Equal(t, fingerprint, build("C", "A", "B").TopologyHash())
+
+	// Keys do not matter.
+	for idx := 0; idx < 10; idx++ {
+		err := ring.Emplace(&Key[RingPayloadType]{
+			InnerKey: &InnerKey{Key: strconv.Itoa(idx)},
+		})
+		require.NoError(t, err)
+	}
+	require.Equal(t, fingerprint, ring.TopologyHash())
+
+	// Any change to the topology does.
+	err := ring.UpdateNode(Node{Identifier: "A", VFactor: 2})
+	require.NoError(t, err)
+	require.NotEqual(t, fingerprint, ring.TopologyHash())
+
+	err = ring.UpdateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+	require.Equal(t, fingerprint, ring.TopologyHash())
+
+	ring.DeleteNode("C")
+	require.NotEqual(t, fingerprint, ring.TopologyHash())
+	require.NotEqual(t, build().TopologyHash(), ring.TopologyHash())
+}
+
+func TestWatcherChangesOnly(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	c := ring.RegisterWatcherWithOptions(Op[RingPayloadType]{
+		Node: "A",
+	}, WatcherOptions{ChangesOnly: true})
+
+	err = ring.Emplace(&Key[RingPayloadType]{
+		InnerKey: &InnerKey{Key: "1"},
+	})
+	require.NoError(t, err)
+
+	go func() {
+		err := ring.CreateNode(Node{
+			Identifier: "A",
+			VFactor:    1,
+		})
+		require.NoError(t, err)
+	}()
+
+	require.Equal(t, Op[RingPayloadType]{
+		Key:        "1",
+		Node:       "A",
+		RingChange: true,
+	}, <-c)
+
+	// Placement, updates, and removals are not delivered, so none of these block.
+	err = ring.Emplace(&Key[RingPayloadType]{
+		InnerKey: &InnerKey{Key: "2"},
+	})
+	require.NoError(t, err)
+
+	err = ring.Update(&Key[RingPayloadType]{
+		InnerKey: &InnerKey{Key: "2"},
+		Value:    RingPayloadType{},
+	})
+	require.NoError(t, err)
+
+	ring.Remove("2")
+
+	go ring.DeleteNode("A")
+
+	require.Equal(t, Op[RingPayloadType]{
+		Key:        "1",
+		Node:       "A",
+		Removed:    true,
+		RingChange: true,
+	}, <-c)
+}
+
+func TestRoute(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{
+		InnerKey: &InnerKey{Key: "1"},
+	})
+	require.NoError(t, err)
+
+	node, slice, hash, err := ring.Route("1")
+	require.NoError(t, err)
+	require.Equal(t, "", node)
+	require.Equal(t, ring.HashKey("1"), hash)
+	require.Equal(t, hash, slice)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	node, slice, hash, err = ring.Route("1")
+	require.NoError(t, err)
+	require.Equal(t, "A", node)
+	require.Equal(t, ring.HashSlice("A", 0), slice)
+	require.Equal(t, ring.HashKey("1"), hash)
+
+	_, _, _, err = ring.Route("2")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestExplain(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}}, "shared")
+	require.NoError(t, err)
+
+	hash, slice, node, err := ring.Explain("1")
+	require.NoError(t, err)
+	require.Equal(t, ring.HashKey("shared"), hash)
+	require.Equal(t, hash, slice)
+	require.Empty(t, node)
+
+	for _, identifier := range []string{"A", "B"} {
+		err = ring.CreateNode(Node{Identifier: identifier, VFactor: 2})
+		require.NoError(t, err)
+	}
+
+	hash, slice, node, err = ring.Explain("1")
+	require.NoError(t, err)
+	require.Equal(t, ring.HashKey("shared"), hash)
+	require.Equal(t, ring.slicesByHash[hash], slice)
+	require.Equal(t, ring.nodesBySlice[slice], node)
+
+	lookup, ok := ring.Lookup("shared")
+	require.True(t, ok)
+	require.Equal(t, lookup, node)
+
+	_, _, _, err = ring.Explain("2")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestUpdateRemovedKey(t *testing.T) {
+	ring, err := New[int]()
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[int]{InnerKey: &InnerKey{Key: "1"}, Value: 1})
+	require.NoError(t, err)
+	ring.Remove("1")
+
+	err = ring.Update(&Key[int]{InnerKey: &InnerKey{Key: "1"}, Value: 2})
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	// A payload without a placement in the ring is not a key of the ring.
+	ring.contentByKey["2"] = 2
+
+	err = ring.Update(&Key[int]{InnerKey: &InnerKey{Key: "2"}, Value: 3})
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	err = ring.UpdateBatch([]*Key[int]{{InnerKey: &InnerKey{Key: "2"}, Value: 3}})
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.Equal(t, []string{"2"}, ring.UpdateBatchBestEffort([]*Key[int]{{InnerKey: &InnerKey{Key: "2"}, Value: 3}}))
+	require.Equal(t, 2, ring.contentByKey["2"])
+}
+
+func TestUpdateBatch(t *testing.T) {
+	ring, err := New[int]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	for idx := 0; idx < 3; idx++ {
+		err = ring.Emplace(&Key[int]{
+			InnerKey: &InnerKey{Key: strconv.Itoa(idx)},
+			Value:    idx,
+		})
+		require.NoError(t, err)
+	}
+
+	// No key is updated if any is missing.
+	err = ring.UpdateBatch([]*Key[int]{
+		{InnerKey: &InnerKey{Key: "0"}, Value: 10},
+		{InnerKey: &InnerKey{Key: "5"}, Value: 15},
+		{InnerKey: &InnerKey{Key: "6"}, Value: 16},
+	})
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.ErrorContains(t, err, `"5"`)
+	require.Equal(t, 0, ring.contentByKey["0"])
+
+	err = ring.UpdateBatch([]*Key[int]{nil})
+	require.ErrorIs(t, err, ErrNilKey)
+
+	c := ring.RegisterWatcher(Op[int]{Node: "A"})
+
+	go func() {
+		err := ring.UpdateBatch([]*Key[int]{
+			{InnerKey: &InnerKey{Key: "2"}, Value: 12},
+			{InnerKey: &InnerKey{Key: "0"}, Value: 10},
+		})
+		require.NoError(t, err)
+	}()
+
+	require.Equal(t, Op[int]{Key: "2", Node: "A", Payload: 12, Updated: true}, <-c)
+	require.Equal(t, Op[int]{Key: "0", Node: "A", Payload: 10, Updated: true}, <-c)
+
+	missing := make(chan []string)
+	go func() {
+		missing <- ring.UpdateBatchBestEffort([]*Key[int]{
+			{InnerKey: &InnerKey{Key: "5"}, Value: 15},
+			{InnerKey: &InnerKey{Key: "1"}, Value: 11},
+			nil,
+			{InnerKey: &InnerKey{Key: "4"}, Value: 14},
+		})
+	}()
+
+	require.Equal(t, Op[int]{Key: "1", Node: "A", Payload: 11, Updated: true}, <-c)
+	require.Equal(t, []string{"5", "4"}, <-missing)
+	require.Equal(t, map[string]int{"0": 10, "1": 11, "2": 12}, ring.contentByKey)
+}
+
+func TestOwnershipUnderAnyHash(t *testing.T) {
+	for name, hash := range testHashes {
+		t.Run(name, func(t *testing.T) {
+			ring := newTestRing(t, hash, func(r *Ring[RingPayloadType]) {
+				r.BaseVFactor = 4
+			})
+
+			for _, identifier := range []string{"A", "B", "C"} {
+				err := ring.CreateNode(Node{Identifier: identifier, VFactor: 2})
+				require.NoError(t, err)
+			}
+
+			for idx := 0; idx < 200; idx++ {
+				err := ring.Emplace(&Key[RingPayloadType]{
+					InnerKey: &InnerKey{Key: strconv.Itoa(idx)},
+				})
+				require.NoError(t, err)
+			}
+
+			ring.DeleteNode("B")
+
+			// Every key belongs to the last slice at or before its hash, wrapping around to the final slice.
+			for key, hash := range ring.hashesByKey {
+				expected := ring.slices[len(ring.slices)-1]
+				for _, slice := range ring.slices {
+					if slice <= hash {
+						expected = slice
+					}
+				}
+
+				require.Equal(t, expected, ring.slicesByHash[hash], key)
+			}
+		})
+	}
+}
+
+func TestNodesFrom(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+	require.Equal(t, []string{}, ring.NodesFrom(0))
+
+	for slice, node := range map[uint64]string{10: "A", 20: "B", 30: "A", 40: "C", 50: "B"} {
+		err = ring.insertSlice(slice, node)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []string{"A", "B", "C"}, ring.NodesFrom(11))
+	require.Equal(t, []string{"A", "B", "C"}, ring.NodesFrom(15))
+	require.Equal(t, []string{"A", "C", "B"}, ring.NodesFrom(35))
+	require.Equal(t, []string{"C", "B", "A"}, ring.NodesFrom(45))
+
+	// Hashes before the first slice are owned by the final slice.
+	require.Equal(t, []string{"B", "A", "C"}, ring.NodesFrom(5))
+	require.Equal(t, []string{"B", "A", "C"}, ring.NodesFrom(55))
+}
+
+func TestReplicas(t *testing.T) {
+	positions := map[string]uint64{
+		"A0": 10, "B0": 20, "A1": 30, "C0": 40, "B1": 50, "C1": 60,
+		"1": 15, "2": 35, "3": 65,
+	}
+	ring := newTestRing(t, injectedHash(MD5, positions), func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 2
+	})
+
+	for _, key := range []string{"1", "2", "3"} {
+		err := ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: key}})
+		require.NoError(t, err)
+	}
+
+	// Keys in the empty container have no replicas.
+	replicas, err := ring.Replicas("1", 2)
+	require.NoError(t, err)
+	require.Empty(t, replicas)
+
+	for _, identifier := range []string{"A", "B", "C"} {
+		err = ring.CreateNode(Node{Identifier: identifier, VFactor: 1})
+		require.NoError(t, err)
+	}
+
+	for key, expected := range map[string][]string{
+		"1": {"A", "B"},
+		"2": {"A", "C"},
+		"3": {"C", "A"},
+	} {
+		replicas, err = ring.Replicas(key, 2)
+		require.NoError(t, err)
+		require.Equal(t, expected, replicas, key)
+	}
+
+	// There are only three distinct nodes.
+	replicas, err = ring.Replicas("1", 5)
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B", "C"}, replicas)
+
+	replicas, err = ring.Replicas("1", 0)
+	require.NoError(t, err)
+	require.Empty(t, replicas)
+
+	_, err = ring.Replicas("4", 2)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestReplicasAgreeAcrossRings(t *testing.T) {
+	newRing := func(nodes []Node) *Ring[RingPayloadType] {
+		ring, err := New(func(r *Ring[RingPayloadType]) {
+			r.BaseVFactor = 8
+		})
+		require.NoError(t, err)
+
+		for _, node := range nodes {
+			err = ring.CreateNode(node)
+			require.NoError(t, err)
+		}
+
+		return ring
+	}
+
+	nodes := []Node{
+		{Identifier: "A", VFactor: 1, Zone: "east"},
+		{Identifier: "B", VFactor: 2, Zone: "east"},
+		{Identifier: "C", VFactor: 1, Zone: "west"},
+		{Identifier: "D", VFactor: 3},
+	}
+	reversed := make([]Node, 0, len(nodes))
+	for idx := len(nodes) - 1; idx >= 0; idx-- {
+		reversed = append(reversed, nodes[idx])
+	}
+
+	// Rings sharing a topology list the same replicas, regardless of the order their nodes were created in.
+	first, second := newRing(nodes), newRing(reversed)
+	for idx := 0; idx < 1000; idx++ {
+		key := strconv.Itoa(idx)
+		require.Equal(t, first.ReplicasForHashKey(key, 3), second.ReplicasForHashKey(key, 3), key)
+	}
+}
+
+func TestReplicasForHashKey(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 4
+	})
+	require.NoError(t, err)
+	require.Empty(t, ring.ReplicasForHashKey("1", 2))
+
+	for _, identifier := range []string{"A", "B", "C", "D"} {
+		err = ring.CreateNode(Node{Identifier: identifier, VFactor: 1})
+		require.NoError(t, err)
+	}
+
+	for idx := 0; idx < 50; idx++ {
+		hashKey := strconv.Itoa(idx)
+		replicas := ring.ReplicasForHashKey(hashKey, 3)
+		require.Len(t, replicas, 3)
+
+		// Nothing is emplaced by the lookup.
+		require.False(t, ring.Contains(hashKey))
+
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "key" + hashKey}}, hashKey)
+		require.NoError(t, err)
+
+		emplaced, err := ring.Replicas("key"+hashKey, 3)
+		require.NoError(t, err)
+		require.Equal(t, emplaced, replicas, hashKey)
+	}
+
+	require.Empty(t, ring.ReplicasForHashKey("1", 0))
+}
+
+func TestEmplaceOrUpdate(t *testing.T) {
+	ring, err := New[int]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	c := ring.RegisterWatcher(Op[int]{Node: "A"})
+
+	go func() {
+		err := ring.EmplaceOrUpdate(&Key[int]{InnerKey: &InnerKey{Key: "1"}, Value: 1}, "shared")
+		require.NoError(t, err)
+		err = ring.EmplaceOrUpdate(&Key[int]{InnerKey: &InnerKey{Key: "1"}, Value: 2}, "other")
+		require.NoError(t, err)
+	}()
+
+	require.Equal(t, Op[int]{Key: "1", Node: "A", Payload: 1}, <-c)
+	require.Equal(t, Op[int]{Key: "1", Node: "A", Payload: 2, Updated: true}, <-c)
+
+	// The update changes the payload without rehashing the key.
+	payload, err := ring.GetPayload("1")
+	require.NoError(t, err)
+	require.Equal(t, 2, payload)
+
+	_, _, hash, err := ring.Route("1")
+	require.NoError(t, err)
+	require.Equal(t, ring.HashKey("shared"), hash)
+
+	require.ErrorIs(t, ring.EmplaceOrUpdate(nil), ErrNilKey)
+}
+
+func TestGetPayload(t *testing.T) {
+	ring, err := New[int]()
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[int]{InnerKey: &InnerKey{Key: "1"}, Value: 5})
+	require.NoError(t, err)
+
+	payload, err := ring.GetPayload("1")
+	require.NoError(t, err)
+	require.Equal(t, 5, payload)
+
+	err = ring.Update(&Key[int]{InnerKey: &InnerKey{Key: "1"}, Value: 6})
+	require.NoError(t, err)
+
+	payload, err = ring.GetPayload("1")
+	require.NoError(t, err)
+	require.Equal(t, 6, payload)
+
+	ring.Remove("1")
+
+	payload, err = ring.GetPayload("1")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.Zero(t, payload)
+}
+
+func TestContainsAndHasNode(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	// The key is parked in the empty container.
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+	require.True(t, ring.Contains("1"))
+	require.False(t, ring.Contains("2"))
+	require.False(t, ring.HasNode("A"))
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+	require.True(t, ring.Contains("1"))
+	require.True(t, ring.HasNode("A"))
+	require.False(t, ring.HasNode("B"))
+
+	ring.Remove("1")
+	ring.DeleteNode("A")
+	require.False(t, ring.Contains("1"))
+	require.False(t, ring.HasNode("A"))
+}
+
+func TestClear(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1, Zone: "z"})
+	require.NoError(t, err)
+
+	c := ring.RegisterWatcher(Op[RingPayloadType]{Node: "A"})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for idx := 0; idx < 2; idx++ {
+			err := ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}}, "shared")
+			require.NoError(t, err)
+		}
+		ring.Clear()
+	}()
+
+	<-c
+	<-c
+	require.Equal(t, Op[RingPayloadType]{Key: "1", Node: "A", Removed: true}, <-c)
+	require.Equal(t, Op[RingPayloadType]{Key: "0", Node: "A", Removed: true}, <-c)
+	<-done
+
+	require.Empty(t, ring.slices)
+	require.Empty(t, ring.hashes)
+	require.Empty(t, ring.empty)
+	require.Empty(t, ring.nodesBySlice)
+	require.Empty(t, ring.vFactorByNode)
+	require.Empty(t, ring.zoneByNode)
+	require.Empty(t, ring.slicesByHash)
+	require.Empty(t, ring.keysByHash)
+	require.Empty(t, ring.contentByKey)
+	require.Empty(t, ring.hashesByKey)
+	require.Empty(t, ring.hashKeyByKey)
+
+	// The watcher remains registered.
+	go func() {
+		err := ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+		require.NoError(t, err)
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "2"}})
+		require.NoError(t, err)
+	}()
+
+	require.Equal(t, Op[RingPayloadType]{Key: "2", Node: "A"}, <-c)
+}
+
+func TestRangeKeys(t *testing.T) {
+	ring, err := New[int]()
+	require.NoError(t, err)
+
+	// The first key is parked in the empty container until the node is created.
+	err = ring.Emplace(&Key[int]{InnerKey: &InnerKey{Key: "0"}, Value: 0})
+	require.NoError(t, err)
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+	err = ring.Emplace(&Key[int]{InnerKey: &InnerKey{Key: "1"}, Value: 1})
+	require.NoError(t, err)
+	ring.DeleteNode("A")
+	err = ring.Emplace(&Key[int]{InnerKey: &InnerKey{Key: "2"}, Value: 2})
+	require.NoError(t, err)
+	err = ring.CreateNode(Node{Identifier: "B", VFactor: 1})
+	require.NoError(t, err)
+
+	payloads := make(map[string]int)
+	ring.RangeKeys(func(key string, node string, payload int) bool {
+		require.Equal(t, "B", node)
+		payloads[key] = payload
+		return true
+	})
+	require.Equal(t, map[string]int{"0": 0, "1": 1, "2": 2}, payloads)
+}
+
+func TestRangeKeysStopsEarly(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	for idx := 0; idx < 3; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+		require.NoError(t, err)
+	}
+
+	calls := 0
+	ring.RangeKeys(func(key string, node string, _ RingPayloadType) bool {
+		require.Empty(t, node)
+		calls++
+		return false
+	})
+	require.Equal(t, 1, calls)
+}
+
+func TestListSlicesForNode(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 2
+	})
+	require.NoError(t, err)
+
+	for _, identifier := range []string{"A", "B"} {
+		err = ring.CreateNode(Node{Identifier: identifier, VFactor: 3})
+		require.NoError(t, err)
+	}
+
+	slices := ring.ListSlicesForNode("A")
+	require.Len(t, slices, 6)
+	require.True(t, sort.SliceIsSorted(slices, func(i, j int) bool { return slices[i] < slices[j] }))
+	for idx := 0; idx < 6; idx++ {
+		require.Contains(t, slices, ring.HashSlice("A", idx))
+	}
+
+	require.NotNil(t, ring.ListSlicesForNode("C"))
+	require.Empty(t, ring.ListSlicesForNode("C"))
+}
+
+func TestGetNodeForKey(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	_, err = ring.GetNodeForKey("1")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+
+	node, err := ring.GetNodeForKey("1")
+	require.NoError(t, err)
+	require.Equal(t, "", node)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	node, err = ring.GetNodeForKey("1")
+	require.NoError(t, err)
+	require.Equal(t, "A", node)
+}
+
+func TestLookup(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 4
+	})
+	require.NoError(t, err)
+
+	_, ok := ring.Lookup("1")
+	require.False(t, ok)
+
+	for _, identifier := range []string{"A", "B", "C"} {
+		err = ring.CreateNode(Node{Identifier: identifier, VFactor: 1})
+		require.NoError(t, err)
+	}
+
+	for idx := 0; idx < 50; idx++ {
+		key := strconv.Itoa(idx)
+
+		node, ok := ring.Lookup(key)
+		require.True(t, ok)
+		_, exists := ring.hashesByKey[key]
+		require.False(t, exists)
+
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: key}})
+		require.NoError(t, err)
+
+		owner, err := ring.GetNodeForKey(key)
+		require.NoError(t, err)
+		require.Equal(t, owner, node)
+	}
+}
+
+func TestLoadState(t *testing.T) {
+	source, err := New(func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 2
+	})
+	require.NoError(t, err)
+
+	for _, node := range []Node{{Identifier: "A", VFactor: 1}, {Identifier: "B", VFactor: 3}} {
+		err = source.CreateNode(node)
+		require.NoError(t, err)
+	}
+
+	for idx := 0; idx < 30; idx++ {
+		err = source.Emplace(&Key[RingPayloadType]{
+			InnerKey: &InnerKey{Key: strconv.Itoa(idx)},
+		}, strconv.Itoa(idx%10))
+		require.NoError(t, err)
+	}
+
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 2
+	})
+	require.NoError(t, err)
+
+	err = ring.LoadState(source.State())
+	require.NoError(t, err)
+	require.Equal(t, source.State(), ring.State())
+	require.Equal(t, source.TopologyState(), ring.TopologyState())
+	require.Equal(t, source.hashes, ring.hashes)
+
+	// A state with a hash belonging to an unknown slice is rejected, leaving the ring unchanged.
+	err = ring.LoadState(&State{
+		NodesBySlice: map[uint64]string{1: "A"},
+		SlicesByHash: map[uint64]uint64{5: 2},
+		HashesByKey:  map[string]uint64{"1": 5},
+	})
+	require.ErrorIs(t, err, ErrInconsistentState)
+	require.Equal(t, source.State(), ring.State())
+
+	// Keys of a state without slices are held in the empty container.
+	err = ring.LoadState(&State{
+		HashesByKey: map[string]uint64{"1": 5},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[uint64]uint64{5: 5}, ring.empty)
+}
+
+func TestStateIsCopy(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+
+	state := ring.State()
+	state.NodesBySlice[1] = "B"
+	state.SlicesByHash[2] = 1
+	state.HashesByKey["2"] = 2
+	delete(state.HashesByKey, "1")
+
+	require.Equal(t, &State{
+		NodesBySlice: map[uint64]string{ring.HashSlice("A", 0): "A"},
+		SlicesByHash: map[uint64]uint64{ring.HashKey("1"): ring.HashSlice("A", 0)},
+		HashesByKey:  map[string]uint64{"1": ring.HashKey("1")},
+	}, ring.State())
+}
+
+func TestStateConcurrentWithEmplace(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for idx := 0; idx < 200; idx++ {
+			err := ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+			require.NoError(t, err)
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			require.Len(t, ring.State().HashesByKey, 200)
+			return
+		default:
+			for range ring.State().SlicesByHash {
+			}
+		}
+	}
+}
+
+func TestEmplaceBatch(t *testing.T) {
+	ring, err := New[int]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[int]{InnerKey: &InnerKey{Key: "0"}})
+	require.NoError(t, err)
+
+	_, err = ring.EmplaceBatch([]*Key[int]{{InnerKey: &InnerKey{Key: "1"}}}, []string{})
+	require.ErrorIs(t, err, ErrBatchLengthMismatch)
+	require.Len(t, ring.hashesByKey, 1)
+
+	c := ring.RegisterWatcher(Op[int]{Node: "A"})
+
+	type result struct {
+		errs []error
+		err  error
+	}
+	results := make(chan result)
+	go func() {
+		errs, err := ring.EmplaceBatch([]*Key[int]{
+			{InnerKey: &InnerKey{Key: "1"}, Value: 1},
+			{InnerKey: &InnerKey{Key: "0"}, Value: 0},
+			nil,
+			{InnerKey: &InnerKey{Key: "2"}, Value: 2},
+			{InnerKey: &InnerKey{Key: "1"}, Value: 3},
+		}, []string{"", "", "", "shared", ""})
+		results <- result{errs: errs, err: err}
+	}()
+
+	require.Equal(t, Op[int]{Key: "1", Node: "A", Payload: 1}, <-c)
+	require.Equal(t, Op[int]{Key: "2", Node: "A", Payload: 2}, <-c)
+
+	r := <-results
+	require.NoError(t, r.err)
+	require.Equal(t, []error{nil, ErrKeyAlreadyExists, ErrNilKey, nil, ErrKeyAlreadyExists}, r.errs)
+	require.Equal(t, ring.HashKey("1"), ring.hashesByKey["1"])
+	require.Equal(t, ring.HashKey("shared"), ring.hashesByKey["2"])
+	require.Equal(t, 1, ring.contentByKey["1"])
+}
+
+func TestEmplaceBatchMergesHashes(t *testing.T) {
+	batch, err := New[RingPayloadType]()
+	require.NoError(t, err)
+	single, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	var keys []*Key[RingPayloadType]
+	var hashKeys []string
+	for idx := 0; idx < 100; idx++ {
+		key := strconv.Itoa(idx)
+		keys = append(keys, &Key[RingPayloadType]{InnerKey: &InnerKey{Key: key}})
+
+		// Share some hashes between keys in the batch, and with keys already in the ring.
+		hashKeys = append(hashKeys, strconv.Itoa(idx%40))
+	}
+
+	for _, ring := range []*Ring[RingPayloadType]{batch, single} {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "existing"}}, "7")
+		require.NoError(t, err)
+	}
+
+	errs, err := batch.EmplaceBatch(keys, hashKeys)
+	require.NoError(t, err)
+	require.Equal(t, make([]error, len(keys)), errs)
+
+	for idx, key := range keys {
+		err = single.Emplace(key, hashKeys[idx])
+		require.NoError(t, err)
+	}
+
+	require.Len(t, batch.hashes, 40)
+	require.Equal(t, single.hashes, batch.hashes)
+	require.Equal(t, single.State(), batch.State())
+}
+
+func benchmarkEmplaceRing(b *testing.B) *Ring[RingPayloadType] {
+	ring, err := New[RingPayloadType]()
+	require.NoError(b, err)
+
+	for idx := 0; idx < 8; idx++ {
+		err = ring.CreateNode(Node{
+			Identifier: strconv.Itoa(idx),
+			VFactor:    100,
+		})
+		require.NoError(b, err)
+	}
+
+	return ring
+}
+
+func BenchmarkEmplaceSingle(b *testing.B) {
+	ring := benchmarkEmplaceRing(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for idx := 0; idx < b.N; idx++ {
+		err := ring.Emplace(&Key[RingPayloadType]{
+			InnerKey: &InnerKey{Key: strconv.Itoa(idx)},
+		})
+		if err != nil {
+			b.Error(err)
+		}
+	}
+}
+
+func BenchmarkEmplaceBatch(b *testing.B) {
+	ring := benchmarkEmplaceRing(b)
+
+	keys := make([]*Key[RingPayloadType], b.N)
+	for idx := range keys {
+		keys[idx] = &Key[RingPayloadType]{
+			InnerKey: &InnerKey{Key: strconv.Itoa(idx)},
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	_, err := ring.EmplaceBatch(keys)
+	if err != nil {
+		b.Error(err)
+	}
+}
+
+func BenchmarkRemoveColliding(b *testing.B) {
+	const colliding = 10000
+
+	ring := benchmarkEmplaceRing(b)
+	for idx := 0; idx < colliding; idx++ {
+		err := ring.Emplace(&Key[RingPayloadType]{
+			InnerKey: &InnerKey{Key: strconv.Itoa(idx)},
+		}, "shared")
+		require.NoError(b, err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for idx := 0; idx < b.N; idx++ {
+		// The oldest key is always last among the colliding keys.
+		key := strconv.Itoa(idx % colliding)
+		ring.Remove(key)
+
+		b.StopTimer()
+		err := ring.Emplace(&Key[RingPayloadType]{
+			InnerKey: &InnerKey{Key: key},
+		}, "shared")
+		if err != nil {
+			b.Error(err)
+		}
+		b.StartTimer()
+	}
+}
+
+func BenchmarkEmplaceSlices(b *testing.B) {
+	for _, slices := range []int{1000, 10000, 100000} {
+		b.Run(strconv.Itoa(slices), func(b *testing.B) {
+			ring, err := New[RingPayloadType]()
+			require.NoError(b, err)
+
+			// The identifiers are suffixed so that slice names of different nodes cannot collide.
+			for idx := 0; idx < slices/100; idx++ {
+				err = ring.CreateNode(Node{
+					Identifier: strconv.Itoa(idx) + "-",
+					VFactor:    100,
+				})
+				require.NoError(b, err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for idx := 0; idx < b.N; idx++ {
+				err := ring.Emplace(&Key[RingPayloadType]{
+					InnerKey: &InnerKey{Key: strconv.Itoa(idx)},
+				})
+				if err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCreateNodeReassignment(b *testing.B) {
+	ring := benchmarkEmplaceRing(b)
+	for idx := 0; idx < 100000; idx++ {
+		err := ring.Emplace(&Key[RingPayloadType]{
+			InnerKey: &InnerKey{Key: strconv.Itoa(idx)},
+		})
+		require.NoError(b, err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for idx := 0; idx < b.N; idx++ {
+		// The new node takes roughly a ninth of the keys from the existing nodes.
+		err := ring.CreateNode(Node{
+			Identifier: "new",
+			VFactor:    100,
+		})
+		if err != nil {
+			b.Error(err)
+		}
+
+		b.StopTimer()
+		ring.DeleteNode("new")
+		b.StartTimer()
+	}
+}
+
+func BenchmarkBuildRing(b *testing.B) {
+	for _, slices := range []int{50000, 500000} {
+		b.Run(strconv.Itoa(slices), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for idx := 0; idx < b.N; idx++ {
+				ring, err := New[RingPayloadType]()
+				require.NoError(b, err)
+
+				for node := 0; node < 100; node++ {
+					err = ring.CreateNode(Node{
+						Identifier: strconv.Itoa(node) + "-",
+						VFactor:    slices / 100,
+					})
+					if err != nil {
+						b.Error(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestInsertSlicesMatchesInsertSlice(t *testing.T) {
+	build := func(insert func(ring *Ring[RingPayloadType], slices []uint64, node string) error) *Ring[RingPayloadType] {
+		ring, err := New[RingPayloadType]()
+		require.NoError(t, err)
+
+		for idx := 0; idx < 200; idx++ {
+			err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+			require.NoError(t, err)
+		}
+
+		for _, node := range []string{"A", "B", "C"} {
+			slices, err := ring.newSlices(node, 0, 20)
+			require.NoError(t, err)
+
+			ring.vFactorByNode[node] = 20
+			_, err = ring.collectMoves(func() error {
+				return insert(ring, slices, node)
+			})
+			require.NoError(t, err)
+		}
+
+		return ring
+	}
+
+	expected := build(func(ring *Ring[RingPayloadType], slices []uint64, node string) error {
+		for _, slice := range slices {
+			err := ring.insertSlice(slice, node)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	actual := build(func(ring *Ring[RingPayloadType], slices []uint64, node string) error {
+		return ring.insertSlices(slices, node)
+	})
+
+	require.Equal(t, expected.State(), actual.State())
+	require.Equal(t, expected.TopologyState(), actual.TopologyState())
+	require.Empty(t, actual.empty)
+
+	require.ErrorIs(t, actual.insertSlices([]uint64{actual.slices[0]}, "D"), ErrSliceAlreadyExists)
+}
+
+func TestLookupViewMatchesLayout(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 4
+	})
+	require.NoError(t, err)
+
+	for _, node := range []Node{{Identifier: "A", VFactor: 1}, {Identifier: "B", VFactor: 3}, {Identifier: "C", VFactor: 2}} {
+		err = ring.CreateNode(node)
+		require.NoError(t, err)
+	}
+	require.Equal(t, ring.Layout(), ring.view.Load().layout)
+
+	// Each change is applied to the previous view, so the view must still match a full layout.
+	ring.DeleteNode("B")
+	require.Equal(t, ring.Layout(), ring.view.Load().layout)
+
+	err = ring.UpdateNode(Node{Identifier: "C", VFactor: 5})
+	require.NoError(t, err)
+	require.Equal(t, ring.Layout(), ring.view.Load().layout)
+
+	err = ring.DrainNode("A")
+	require.NoError(t, err)
+	require.Equal(t, ring.Layout(), ring.view.Load().layout)
+
+	err = ring.CreateNode(Node{Identifier: "B", VFactor: 1})
+	require.NoError(t, err)
+	require.Equal(t, ring.Layout(), ring.view.Load().layout)
+}
+
+func TestLookupWhileLocked(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 2})
+	require.NoError(t, err)
+
+	// Lookups read the topology view, so they complete while a mutation holds the lock.
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	done := make(chan string)
+	go func() {
+		node, _ := ring.Lookup("key")
+		done <- node
+	}()
+
+	select {
+	case node := <-done:
+		require.Equal(t, "A", node)
+	case <-time.After(time.Second):
+		t.Fatal("lookup blocked on the ring lock")
+	}
+}
+
+func TestGetNodeForKeyWhileLocked(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	for _, identifier := range []string{"A", "B"} {
+		err = ring.CreateNode(Node{Identifier: identifier, VFactor: 2})
+		require.NoError(t, err)
+	}
+
+	for _, key := range []string{"1", "2"} {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: key}})
+		require.NoError(t, err)
+	}
+
+	err = ring.Pin("2", "B")
+	require.NoError(t, err)
+
+	expected, err := ring.GetNodeForKey("1")
+	require.NoError(t, err)
+
+	// Keys are resolved against the topology view, so they complete while a mutation holds the lock.
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	done := make(chan []string)
+	go func() {
+		var nodes []string
+		for _, key := range []string{"1", "2"} {
+			node, _ := ring.GetNodeForKey(key)
+			nodes = append(nodes, node)
+		}
+		done <- nodes
+	}()
+
+	select {
+	case nodes := <-done:
+		require.Equal(t, []string{expected, "B"}, nodes)
+	case <-time.After(time.Second):
+		t.Fatal("GetNodeForKey blocked on the ring lock")
+	}
+}
+
+func TestDrainNodePublishesEachSlice(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 4
+	})
+	require.NoError(t, err)
+
+	for _, identifier := range []string{"A", "B"} {
+		err = ring.CreateNode(Node{Identifier: identifier, VFactor: 1})
+		require.NoError(t, err)
+	}
+
+	// Every slice drained is published, so Lookup observes the drain progressively.
+	for ring.drainSlice("A") {
+		require.Equal(t, ring.Layout(), ring.view.Load().layout)
+	}
+	require.Equal(t, ring.Layout(), ring.view.Load().layout)
+}
+
+func BenchmarkLookupDuringCreateNode(b *testing.B) {
+	ring := benchmarkEmplaceRing(b)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Millisecond):
+			}
+
+			err := ring.CreateNode(Node{Identifier: "new", VFactor: 100})
+			if err != nil {
+				b.Error(err)
+			}
+			ring.DeleteNode("new")
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		var idx int
+		for pb.Next() {
+			_, ok := ring.Lookup(strconv.Itoa(idx))
+			if !ok {
+				b.Error("ring has no slices")
+			}
+			idx++
+		}
+	})
+
+	b.StopTimer()
+	close(stop)
+	<-done
+}
+
+func TestRemoveBatch(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	for idx := 0; idx < 4; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{
+			InnerKey: &InnerKey{Key: strconv.Itoa(idx)},
+		}, strconv.Itoa(idx%2))
+		require.NoError(t, err)
+	}
+
+	c := ring.RegisterWatcher(Op[RingPayloadType]{Node: "A"})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ring.RemoveBatch([]string{"0", "5", "2", "1"})
+	}()
+
+	for _, key := range []string{"0", "2", "1"} {
+		require.Equal(t, Op[RingPayloadType]{Key: key, Node: "A", Removed: true}, <-c)
+	}
+	<-done
+
+	require.Equal(t, map[string]RingPayloadType{"3": {}}, ring.contentByKey)
+	require.Equal(t, map[string]uint64{"3": ring.HashKey("1")}, ring.hashesByKey)
+	require.Equal(t, []uint64{ring.HashKey("1")}, ring.hashes)
+	require.Len(t, ring.keysByHash[ring.HashKey("1")], 1)
+	require.Empty(t, ring.keysByHash[ring.HashKey("0")])
+}
+
+func TestCounts(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 2
+	})
+	require.NoError(t, err)
+
+	for idx := 0; idx < 3; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+		require.NoError(t, err)
+	}
+
+	// Keys in the empty container are counted.
+	require.Equal(t, 3, ring.KeyCount())
+	require.Zero(t, ring.NodeCount())
+	require.Zero(t, ring.SliceCount())
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+	err = ring.CreateNode(Node{Identifier: "B", VFactor: 3})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, ring.NodeCount())
+	require.Equal(t, 8, ring.SliceCount())
+
+	ring.Remove("1")
+	require.Equal(t, 2, ring.KeyCount())
+
+	ring.DeleteNode("B")
+	require.Equal(t, 1, ring.NodeCount())
+	require.Equal(t, 2, ring.SliceCount())
+	require.Equal(t, 2, ring.KeyCount())
+}
+
+func TestUpdateConcurrentWithCreateNode(t *testing.T) {
+	ring, err := New[int]()
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[int]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for idx := 0; idx < 50; idx++ {
+			err := ring.CreateNode(Node{Identifier: strconv.Itoa(idx), VFactor: 1})
+			require.NoError(t, err)
+		}
+	}()
+
+	for idx := 0; ; idx++ {
+		select {
+		case <-done:
+			require.NoError(t, ring.Update(&Key[int]{InnerKey: &InnerKey{Key: "1"}, Value: idx}))
+			require.Equal(t, idx, ring.contentByKey["1"])
+			return
+		default:
+			require.NoError(t, ring.Update(&Key[int]{InnerKey: &InnerKey{Key: "1"}, Value: idx}))
+		}
+	}
+}
+
+func TestWatchersSharingFilter(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	first := ring.RegisterWatcher(Op[RingPayloadType]{Node: "A"})
+	second := ring.RegisterWatcher(Op[RingPayloadType]{Node: "A"})
+
+	go func() {
+		err := ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+		require.NoError(t, err)
+	}()
+
+	expected := Op[RingPayloadType]{Key: "1", Node: "A"}
+	require.Equal(t, expected, <-first)
+	require.Equal(t, expected, <-second)
+
+	// Deregistering one channel leaves the other registered.
+	ring.DeregisterWatcherChannel(first)
+	_, ok := <-first
+	require.False(t, ok)
+
+	go ring.Remove("1")
+
+	require.Equal(t, Op[RingPayloadType]{Key: "1", Node: "A", Removed: true}, <-second)
+
+	ring.DeregisterWatcherChannel(first)
+	ring.DeregisterWatcher(Op[RingPayloadType]{Node: "A"})
+	_, ok = <-second
+	require.False(t, ok)
+	require.Empty(t, ring.watchers)
+}
+
+func TestRegisterWatcherCtx(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := ring.RegisterWatcherCtx(ctx, Op[RingPayloadType]{Node: "A"})
+
+	go func() {
+		err := ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+		require.NoError(t, err)
+	}()
+	require.Equal(t, Op[RingPayloadType]{Key: "1", Node: "A"}, <-c)
+
+	cancel()
+
+	_, ok := <-c
+	require.False(t, ok)
+
+	// Changes no longer block on the cancelled watcher.
+	ring.Remove("1")
+
+	// Deregistering manually first leaves nothing to do once the context is done.
+	ctx, cancel = context.WithCancel(context.Background())
+	c = ring.RegisterWatcherCtx(ctx, Op[RingPayloadType]{Node: "A"})
+
+	ring.DeregisterWatcher(Op[RingPayloadType]{Node: "A"})
+	_, ok = <-c
+	require.False(t, ok)
+
+	cancel()
+}
+
+func TestWatcherBuffer(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.WatcherBuffer = 2
+	})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	c := ring.RegisterWatcher(Op[RingPayloadType]{Node: "A"})
+
+	// Emplacing does not block until the buffer is full.
+	for idx := 0; idx < 2; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+		require.NoError(t, err)
+	}
+
+	emplaced := make(chan struct{})
+	go func() {
+		defer close(emplaced)
+		err := ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "2"}})
+		require.NoError(t, err)
+	}()
+
+	select {
+	case <-emplaced:
+		t.Fatal("emplace did not block on a full buffer")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// Ops are delivered in order.
+	for idx := 0; idx < 3; idx++ {
+		require.Equal(t, Op[RingPayloadType]{Key: strconv.Itoa(idx), Node: "A"}, <-c)
+	}
+	<-emplaced
+}
+
+func TestLossyWatcher(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	filter := Op[RingPayloadType]{Node: "A"}
+	ring.RegisterWatcherWithOptions(filter, WatcherOptions{Lossy: true})
+
+	// The watcher never reads, yet emplacing does not stall.
+	for idx := 0; idx < 5; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, uint64(5), ring.DroppedOps(filter))
+	require.Zero(t, ring.DroppedOps(Op[RingPayloadType]{Node: "B"}))
+
+	ring.DeregisterWatcher(filter)
+	require.Zero(t, ring.DroppedOps(filter))
+}
+
+func TestRegisterWatcherWithReplay(t *testing.T) {
+	ring := newTestRing(t, injectedHash(MD5, map[string]uint64{"A0": 0, "1": 100, "2": 200}), func(r *Ring[int]) {
+		r.BaseVFactor = 1
+	})
+
+	err := ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	for _, value := range []int{2, 1} {
+		err = ring.Emplace(&Key[int]{InnerKey: &InnerKey{Key: strconv.Itoa(value)}, Value: value})
+		require.NoError(t, err)
+	}
+
+	c := ring.RegisterWatcherWithReplay(Op[int]{Node: "A"})
+	empty := ring.RegisterWatcherWithReplay(Op[int]{Node: "B"})
+	require.Empty(t, empty)
+
+	// Existing keys are replayed in hash order, before any later change.
+	go func() {
+		err := ring.Emplace(&Key[int]{InnerKey: &InnerKey{Key: "3"}, Value: 3})
+		require.NoError(t, err)
+	}()
+
+	for value := 1; value <= 3; value++ {
+		require.Equal(t, Op[int]{Key: strconv.Itoa(value), Node: "A", Payload: value}, <-c)
+	}
+}
+
+func TestWatchAll(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.BaseVFactor = 1
+	})
+	require.NoError(t, err)
+
+	all := ring.WatchAll()
+	filtered := ring.RegisterWatcher(Op[RingPayloadType]{Node: "A"})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+		require.NoError(t, err)
+		err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+		require.NoError(t, err)
+		ring.Remove("1")
+	}()
+
+	// Ops are received regardless of their node, as well as by the watcher with a matching filter.
+	require.Equal(t, Op[RingPayloadType]{Key: "1"}, <-all)
+	require.Equal(t, Op[RingPayloadType]{Key: "1", Node: "A", RingChange: true}, <-filtered)
+	require.Equal(t, Op[RingPayloadType]{Key: "1", Node: "A", RingChange: true}, <-all)
+	require.Equal(t, Op[RingPayloadType]{Key: "1", Node: "A", Removed: true}, <-filtered)
+	require.Equal(t, Op[RingPayloadType]{Key: "1", Node: "A", Removed: true}, <-all)
+	<-done
+
+	ring.UnwatchAll(all)
+	ring.UnwatchAll(all)
+	_, ok := <-all
+	require.False(t, ok)
+
+	// Unwatching leaves filtered watchers registered.
+	go func() {
+		err := ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "2"}})
+		require.NoError(t, err)
+	}()
+	require.Equal(t, Op[RingPayloadType]{Key: "2", Node: "A"}, <-filtered)
+}
+
+func TestNotifyTimeout(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.NotifyTimeout = 10 * time.Millisecond
+	})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	filter := Op[RingPayloadType]{Node: "A"}
+	c := ring.RegisterWatcher(filter)
+
+	// The watcher never reads, yet emplacing returns once the timeout elapses.
+	emplaced := make(chan struct{})
+	go func() {
+		defer close(emplaced)
+		for idx := 0; idx < 2; idx++ {
+			err := ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+			require.NoError(t, err)
+		}
+	}()
+
+	select {
+	case <-emplaced:
+	case <-time.After(time.Second):
+		t.Fatal("emplace blocked on a stuck watcher")
+	}
+	require.Equal(t, uint64(2), ring.DroppedOps(filter))
+
+	// The watcher remains registered, receiving ops once it reads again.
+	go func() {
+		err := ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "2"}})
+		require.NoError(t, err)
+	}()
+	require.Equal(t, Op[RingPayloadType]{Key: "2", Node: "A"}, <-c)
+}
+
+func TestRingEmptinessCallbacks(t *testing.T) {
+	var nonEmpty, empty int
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.OnRingNonEmpty = func() { nonEmpty++ }
+		r.OnRingEmpty = func() { empty++ }
+	})
+	require.NoError(t, err)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+	require.Zero(t, nonEmpty)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 3})
+	require.NoError(t, err)
+	require.Equal(t, 1, nonEmpty)
+
+	// Further topology changes which keep slices in the ring fire neither callback.
+	err = ring.CreateNode(Node{Identifier: "B", VFactor: 1})
+	require.NoError(t, err)
+	err = ring.UpdateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+	ring.DeleteNode("A")
+	require.Equal(t, 1, nonEmpty)
+	require.Zero(t, empty)
+
+	ring.DeleteNode("B")
+	require.Equal(t, 1, nonEmpty)
+	require.Equal(t, 1, empty)
+
+	node, err := ring.GetNodeForKey("1")
+	require.NoError(t, err)
+	require.Empty(t, node)
+
+	ring.DeleteNode("B")
+	require.Equal(t, 1, empty)
+}
+
+func TestPendingKeys(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	require.Equal(t, []string{}, ring.PendingKeys())
+
+	for _, key := range []*InnerKey{{Key: "b", Order: 1}, {Key: "a", Order: 0}} {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: key}, "shared")
+		require.NoError(t, err)
+	}
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "c"}})
+	require.NoError(t, err)
+
+	expected := []string{"a", "b", "c"}
+	if ring.HashKey("c") < ring.HashKey("shared") {
+		expected = []string{"c", "a", "b"}
+	}
+	require.Equal(t, expected, ring.PendingKeys())
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{}, ring.PendingKeys())
+}
+
+func TestStrictCollisions(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.StrictCollisions = true
+	})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	// Hashing the key by the name of the node's slice places it exactly on the slice.
+	sliceName := ring.ToSliceName("A", 0)
+	key := &Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}}
+
+	require.ErrorIs(t, ring.Emplace(key, sliceName), ErrKeyHashCollision)
+	require.ErrorIs(t, ring.EmplaceOrUpdate(key, sliceName), ErrKeyHashCollision)
+	require.ErrorIs(t, ring.EmplaceTTL(key, time.Minute, sliceName), ErrKeyHashCollision)
+	require.ErrorIs(t, ring.EmplaceColocated(sliceName, []*Key[RingPayloadType]{key}), ErrKeyHashCollision)
+
+	errs, err := ring.EmplaceBatch([]*Key[RingPayloadType]{key}, []string{sliceName})
+	require.NoError(t, err)
+	require.ErrorIs(t, errs[0], ErrKeyHashCollision)
+	require.False(t, ring.Contains("1"))
+
+	// Without StrictCollisions, the key is emplaced onto the slice.
+	ring.StrictCollisions = false
+	err = ring.Emplace(key, sliceName)
+	require.NoError(t, err)
+
+	node, err := ring.GetNodeForKey("1")
+	require.NoError(t, err)
+	require.Equal(t, "A", node)
+}
+
+func TestSalts(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	// Unsalted, a key hashed by the name of a slice collides with the slice.
+	sliceName := ring.ToSliceName("A", 0)
+	require.Equal(t, ring.HashSlice("A", 0), ring.HashKey(sliceName))
+	require.Equal(t, MD5("1"), ring.HashKey("1"))
+
+	salted, err := New(WithSalts[RingPayloadType]("slice:", "key:"), func(r *Ring[RingPayloadType]) {
+		r.StrictCollisions = true
+	})
+	require.NoError(t, err)
+
+	require.NotEqual(t, salted.HashSlice("A", 0), salted.HashKey(sliceName))
+	require.Equal(t, MD5("slice:"+sliceName), salted.HashSlice("A", 0))
+	require.Equal(t, MD5("key:1"), salted.HashKey("1"))
+
+	err = salted.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+	require.Equal(t, []uint64{salted.HashSlice("A", 0)}, salted.ListSlicesForNode("A"))
+
+	err = salted.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}}, sliceName)
+	require.NoError(t, err)
+	require.Equal(t, salted.HashKey(sliceName), salted.State().HashesByKey["1"])
+
+	node, ok := salted.Lookup(sliceName)
+	require.True(t, ok)
+	require.Equal(t, "A", node)
+}
+
+func TestListNodesDetailed(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	require.Empty(t, ring.ListNodesDetailed())
+
+	nodes := []Node{
+		{Identifier: "A", VFactor: 1},
+		{Identifier: "B", VFactor: 3, Zone: "east"},
+		{Identifier: "C", VFactor: 2},
+	}
+	for _, node := range nodes {
+		err = ring.CreateNode(node)
+		require.NoError(t, err)
+	}
+
+	require.ElementsMatch(t, nodes, ring.ListNodesDetailed())
+}
+
+func TestSortedListNodes(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	require.Equal(t, []string{}, ring.SortedListNodes())
+
+	for _, identifier := range []string{"delta", "alpha", "charlie", "echo", "bravo"} {
+		err = ring.CreateNode(Node{Identifier: identifier, VFactor: 1})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []string{"alpha", "bravo", "charlie", "delta", "echo"}, ring.SortedListNodes())
+}
+
+func TestCreateNodes(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+	for idx := 0; idx < 20; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+		require.NoError(t, err)
+	}
+
+	state, topology := ring.State(), ring.TopologyState()
+
+	for _, nodes := range [][]Node{
+		{{Identifier: "B", VFactor: 1}, {Identifier: "A", VFactor: 1}, {Identifier: "C", VFactor: 1}},
+		{{Identifier: "B", VFactor: 1}, {Identifier: "B", VFactor: 2}, {Identifier: "C", VFactor: 1}},
+	} {
+		require.ErrorIs(t, ring.CreateNodes(nodes), ErrNodeAlreadyExists)
+		require.Equal(t, state, ring.State())
+		require.Equal(t, topology, ring.TopologyState())
+		require.ElementsMatch(t, []string{"A"}, ring.ListNodes())
+	}
+
+	require.ErrorIs(t, ring.CreateNodes([]Node{{Identifier: "B", VFactor: 1}, {Identifier: "C"}}), ErrInvalidVFactor)
+	require.Equal(t, topology, ring.TopologyState())
+
+	expected, err := NewWithNodes[RingPayloadType]([]Node{{Identifier: "A", VFactor: 1}})
+	require.NoError(t, err)
+	for idx := 0; idx < 20; idx++ {
+		err = expected.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+		require.NoError(t, err)
+	}
+	for _, identifier := range []string{"B", "C"} {
+		err = expected.CreateNode(Node{Identifier: identifier, VFactor: 2})
+		require.NoError(t, err)
+	}
+
+	err = ring.CreateNodes([]Node{{Identifier: "B", VFactor: 2}, {Identifier: "C", VFactor: 2}})
+	require.NoError(t, err)
+	require.Equal(t, expected.State(), ring.State())
+	require.Equal(t, expected.TopologyState(), ring.TopologyState())
+}
+
+func TestCreateNodesSliceCollision(t *testing.T) {
+	// "A10" is the name of both the eleventh slice of node "A" and the first slice of node "A1".
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNodes([]Node{{Identifier: "A1", VFactor: 1}, {Identifier: "A", VFactor: 11}})
+	require.ErrorIs(t, err, ErrSliceHashCollision)
+	require.Empty(t, ring.ListNodes())
+	require.Empty(t, ring.TopologyState().Slices)
+}
+
+func TestRemoveErrAndDeleteNodeErr(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+	require.NoError(t, err)
+
+	ring.WatcherBuffer = 1
+	c := ring.RegisterWatcher(Op[RingPayloadType]{Node: "A"})
+
+	require.NoError(t, ring.RemoveErr("1"))
+	require.Equal(t, Op[RingPayloadType]{Key: "1", Node: "A", Removed: true}, <-c)
+	require.ErrorIs(t, ring.RemoveErr("1"), ErrKeyNotFound)
+	require.False(t, ring.Contains("1"))
+
+	require.NoError(t, ring.DeleteNodeErr("A"))
+	require.ErrorIs(t, ring.DeleteNodeErr("A"), ErrNodeNotFound)
+	require.False(t, ring.HasNode("A"))
+}
+
+func TestRemoveSharedHashFromEmpty(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	for _, key := range []string{"1", "2"} {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: key}}, "shared")
+		require.NoError(t, err)
+	}
+
+	// The shared hash stays in the empty container until its last key is removed.
+	ring.Remove("1")
+	require.Len(t, ring.empty, 1)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	node, err := ring.GetNodeForKey("2")
+	require.NoError(t, err)
+	require.Equal(t, "A", node)
+
+	ring.Remove("2")
+	require.Empty(t, ring.hashes)
+	require.Empty(t, ring.slicesByHash)
+}
+
+func TestLayout(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	require.Empty(t, ring.Layout())
+
+	for _, identifier := range []string{"A", "B"} {
+		err = ring.CreateNode(Node{Identifier: identifier, VFactor: 2})
+		require.NoError(t, err)
+	}
+
+	var expected []SlicePosition
+	for _, identifier := range []string{"A", "B"} {
+		for idx := 0; idx < 2; idx++ {
+			expected = append(expected, SlicePosition{Hash: ring.HashSlice(identifier, idx), Node: identifier})
+		}
+	}
+	sort.Slice(expected, func(i, j int) bool { return expected[i].Hash < expected[j].Hash })
+
+	require.Equal(t, expected, ring.Layout())
+}
+
+func TestArcCoverage(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	require.Empty(t, ring.ArcCoverage())
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 3})
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"A": math.MaxUint64}, ring.ArcCoverage())
+
+	err = ring.CreateNode(Node{Identifier: "B", VFactor: 2})
+	require.NoError(t, err)
+
+	coverage := ring.ArcCoverage()
+	require.Len(t, coverage, 2)
+
+	// The spans cover the entire hash space, so their sum wraps around to exactly zero.
+	var total uint64
+	for identifier, span := range coverage {
+		require.Positive(t, span)
+		total += span
+
+		share, err := ring.NodeShare(identifier)
+		require.NoError(t, err)
+		require.InDelta(t, share, float64(span)/math.Exp2(64), 1e-9)
+	}
+	require.Zero(t, total)
+}
+
+func TestPruneStaleWatchers(t *testing.T) {
+	clock := newFakeClock()
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.Clock = clock
+	})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	abandoned := ring.RegisterWatcher(Op[RingPayloadType]{Node: "A"})
+	idle := ring.RegisterWatcher(Op[RingPayloadType]{Node: "B"})
+
+	// Nothing is pruned while no op is waiting on the watchers.
+	clock.Advance(time.Hour)
+	require.Zero(t, ring.PruneStaleWatchers(time.Minute))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "1"}})
+		require.NoError(t, err)
+	}()
+
+	// The op waits on the abandoned watcher, which last accepted an op when it was registered.
+	require.Eventually(t, func() bool {
+		ring.watchMu.Lock()
+		defer ring.watchMu.Unlock()
+		return ring.watchers[ring.Filter(Op[RingPayloadType]{Node: "A"})][0].undelivered.Load()
+	}, time.Second, time.Millisecond)
+	require.Equal(t, 1, ring.PruneStaleWatchers(time.Minute))
+	<-done
+
+	_, ok := <-abandoned
+	require.False(t, ok)
+	require.Zero(t, ring.PruneStaleWatchers(time.Minute))
+
+	// The idle watcher remains registered.
+	go func() {
+		err := ring.CreateNode(Node{Identifier: "B", VFactor: 100})
+		require.NoError(t, err)
+	}()
+	require.Equal(t, Op[RingPayloadType]{Key: "1", Node: "B", RingChange: true}, <-idle)
+}
+
+func TestPruneStaleLossyWatchers(t *testing.T) {
+	clock := newFakeClock()
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.Clock = clock
+		r.WatcherBuffer = 1
+	})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+
+	abandoned := ring.RegisterWatcherWithOptions(Op[RingPayloadType]{Node: "A"}, WatcherOptions{Lossy: true})
+	reading := ring.RegisterWatcherWithOptions(Op[RingPayloadType]{Node: "A"}, WatcherOptions{Lossy: true})
+
+	// The first op fills the buffer of each watcher, and the second is dropped.
+	for _, key := range []string{"1", "2"} {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: key}})
+		require.NoError(t, err)
+	}
+	require.Equal(t, uint64(2), ring.DroppedOps(Op[RingPayloadType]{Node: "A"}))
+
+	// Nothing is pruned until the window since the watchers last accepted an op has passed.
+	require.Zero(t, ring.PruneStaleWatchers(time.Minute))
+
+	// The watcher which drained its buffer is still being read, so only the abandoned watcher is pruned.
+	<-reading
+	clock.Advance(time.Hour)
+	require.Equal(t, 1, ring.PruneStaleWatchers(time.Minute))
+
+	require.Equal(t, Op[RingPayloadType]{Key: "1", Node: "A"}, <-abandoned)
+	_, ok := <-abandoned
+	require.False(t, ok)
+
+	err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: "3"}})
+	require.NoError(t, err)
+	require.Equal(t, Op[RingPayloadType]{Key: "3", Node: "A"}, <-reading)
+}
@@ -0,0 +1,138 @@
+package ring
+
+// SimulateCreateNode reports how many keys would change owners if the node were created, by creating it in a copy
+// of the ring. The ring itself is left unchanged and no watchers are notified. It returns the same errors as
+// CreateNode, in which case nothing would move.
+func (ring *Ring[T]) SimulateCreateNode(node Node) (moved int, err error) {
+	if node.VFactor < 1 {
+		return 0, ErrInvalidVFactor
+	}
+
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	_, ok := ring.vFactorByNode[node.Identifier]
+	if ok {
+		return 0, ErrNodeAlreadyExists
+	}
+
+	slices, err := ring.newSlices(node.Identifier, 0, node.VFactor*ring.BaseVFactor)
+	if err != nil {
+		return 0, err
+	}
+
+	return ring.simulateCreateNode(node.Identifier, slices)
+}
+
+// simulateCreateNode counts the keys which would change owners if the node were created with the slices.
+func (ring *Ring[T]) simulateCreateNode(identifier string, slices []uint64) (int, error) {
+	clone, err := ring.clone()
+	if err != nil {
+		return 0, err
+	}
+
+	return clone.collectMoves(func() error {
+		return clone.insertSlices(slices, identifier)
+	})
+}
+
+// SimulateDeleteNode reports how many keys would change owners if the node were deleted, by deleting it in a copy
+// of the ring. Keys which would move into the empty container are counted. The ring itself is left unchanged and
+// no watchers are notified. Nothing would move if no node with the given identifier exists.
+func (ring *Ring[T]) SimulateDeleteNode(identifier string) (moved int) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	_, ok := ring.vFactorByNode[identifier]
+	if !ok {
+		return 0
+	}
+
+	return ring.simulateDeleteNode(identifier)
+}
+
+// simulateDeleteNode counts the keys which would change owners if the existing node were deleted.
+func (ring *Ring[T]) simulateDeleteNode(identifier string) (moved int) {
+	clone, err := ring.clone()
+	if err != nil {
+		return 0
+	}
+	vFactor := clone.vFactorByNode[identifier]
+
+	// Keys pinned to the node return to their hash-based placement, moving at once unless the node owns their hashes.
+	for key, node := range clone.pinnedKeys {
+		if node == identifier && clone.ownerOfHash(clone.hashesByKey[key]) != (owner{node: identifier}) {
+			moved++
+		}
+	}
+	clone.unpinNode(identifier)
+
+	moves, _ := clone.collectMoves(func() error {
+		clone.removeSlices(clone.nodeSlices(identifier, 0, vFactor*clone.BaseVFactor))
+		return nil
+	})
+
+	return moved + moves
+}
+
+// checkMovement returns ErrMovementExceeded if the topology change would move more than the MaxMovementFraction
+// of all keys, applying it to a copy of the ring to count the keys which would change owners. Any error from the
+// change is returned instead. Nothing is copied unless MaxMovementFraction is set.
+func (ring *Ring[T]) checkMovement(change func(clone *Ring[T]) error) error {
+	if ring.MaxMovementFraction <= 0 {
+		return nil
+	}
+
+	clone, err := ring.clone()
+	if err != nil {
+		return err
+	}
+
+	moved, err := clone.collectMoves(func() error {
+		return change(clone)
+	})
+	if err != nil {
+		return err
+	}
+
+	if ring.exceedsMaxMovement(moved) {
+		return ErrMovementExceeded
+	}
+
+	return nil
+}
+
+// exceedsMaxMovement reports whether moving the number of keys would exceed the MaxMovementFraction of all keys.
+func (ring *Ring[T]) exceedsMaxMovement(moved int) bool {
+	if ring.MaxMovementFraction <= 0 || len(ring.hashesByKey) == 0 {
+		return false
+	}
+
+	return float64(moved)/float64(len(ring.hashesByKey)) > ring.MaxMovementFraction
+}
+
+// clone creates a copy of the ring's nodes, slices, keys and pins. Watchers and event streams are not copied,
+// so changes to the clone are not observed.
+func (ring *Ring[T]) clone() (*Ring[T], error) {
+	clone, err := New(func(r *Ring[T]) {
+		r.Hash = ring.Hash
+		r.BaseVFactor = ring.BaseVFactor
+		r.ToSliceName = ring.ToSliceName
+		r.SliceSalt = ring.SliceSalt
+		r.KeySalt = ring.KeySalt
+		r.IndexBy = ring.IndexBy
+		r.Clock = ring.Clock
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The clone is never looked up, so its topology view is never published.
+	clone.noView = true
+	err = clone.restore(ring.snapshot())
+	if err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
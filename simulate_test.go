@@ -0,0 +1,161 @@
+package ring
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// observedMoves counts the keys removed from their nodes by a ring change, as observed by the watcher.
+func observedMoves(c chan Op[RingPayloadType]) int {
+	var moved int
+	for len(c) > 0 {
+		op := <-c
+		if op.Removed && op.RingChange {
+			moved++
+		}
+	}
+
+	return moved
+}
+
+func simulatedRing(t *testing.T) (*Ring[RingPayloadType], chan Op[RingPayloadType]) {
+	ring, err := NewWithNodes[RingPayloadType]([]Node{{Identifier: "A", VFactor: 2}, {Identifier: "B", VFactor: 3}})
+	require.NoError(t, err)
+
+	for idx := 0; idx < 100; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+		require.NoError(t, err)
+	}
+
+	ring.WatcherBuffer = 200
+	return ring, ring.WatchAll()
+}
+
+func TestSimulateCreateNode(t *testing.T) {
+	ring, c := simulatedRing(t)
+	state := ring.State()
+
+	moved, err := ring.SimulateCreateNode(Node{Identifier: "C", VFactor: 2})
+	require.NoError(t, err)
+	require.Positive(t, moved)
+
+	// The ring itself is unchanged and nothing is notified.
+	require.Equal(t, state, ring.State())
+	require.Empty(t, c)
+
+	err = ring.CreateNode(Node{Identifier: "C", VFactor: 2})
+	require.NoError(t, err)
+	require.Equal(t, moved, observedMoves(c))
+
+	_, err = ring.SimulateCreateNode(Node{Identifier: "C", VFactor: 2})
+	require.ErrorIs(t, err, ErrNodeAlreadyExists)
+	_, err = ring.SimulateCreateNode(Node{Identifier: "D"})
+	require.ErrorIs(t, err, ErrInvalidVFactor)
+}
+
+func TestSimulateDeleteNode(t *testing.T) {
+	ring, c := simulatedRing(t)
+
+	// Pin a key owned by each node onto A, so that deleting A moves both.
+	for _, identifier := range []string{"A", "B"} {
+		ops, err := ring.NodeSnapshot(identifier)
+		require.NoError(t, err)
+		err = ring.Pin(ops[0].Key, "A")
+		require.NoError(t, err)
+	}
+	_ = observedMoves(c)
+
+	state := ring.State()
+	require.Zero(t, ring.SimulateDeleteNode("C"))
+
+	for _, identifier := range []string{"A", "B"} {
+		moved := ring.SimulateDeleteNode(identifier)
+		require.Positive(t, moved)
+
+		require.Equal(t, state, ring.State())
+		require.Empty(t, c)
+
+		ring.DeleteNode(identifier)
+		require.Equal(t, moved, observedMoves(c))
+		state = ring.State()
+	}
+}
+
+func TestMaxMovementFraction(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{Identifier: "A", VFactor: 1})
+	require.NoError(t, err)
+	for idx := 0; idx < 100; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+		require.NoError(t, err)
+	}
+
+	// A node with 3 times the slices of A takes well over half of the keys.
+	large := Node{Identifier: "B", VFactor: 3}
+	moved, err := ring.SimulateCreateNode(large)
+	require.NoError(t, err)
+	require.Greater(t, moved, 50)
+
+	ring.MaxMovementFraction = 0.5
+	state := ring.State()
+
+	require.ErrorIs(t, ring.CreateNode(large), ErrMovementExceeded)
+	require.Equal(t, state, ring.State())
+	require.False(t, ring.HasNode("B"))
+
+	ring.MaxMovementFraction = 0
+	err = ring.CreateNode(large)
+	require.NoError(t, err)
+
+	// Deleting a node moves every key it owns, so only deleting A is within the cap.
+	ring.MaxMovementFraction = 0.5
+	state = ring.State()
+
+	require.ErrorIs(t, ring.TryDeleteNode("B"), ErrMovementExceeded)
+	require.Equal(t, state, ring.State())
+	require.ErrorIs(t, ring.TryDeleteNode("C"), ErrNodeNotFound)
+
+	err = ring.TryDeleteNode("A")
+	require.NoError(t, err)
+	require.Equal(t, []string{"B"}, ring.ListNodes())
+}
+
+func TestMaxMovementFractionTopologyChanges(t *testing.T) {
+	ring, err := New[RingPayloadType]()
+	require.NoError(t, err)
+
+	for _, node := range []string{"A", "B"} {
+		err = ring.CreateNode(Node{Identifier: node, VFactor: 1})
+		require.NoError(t, err)
+	}
+	for idx := 0; idx < 100; idx++ {
+		err = ring.Emplace(&Key[RingPayloadType]{InnerKey: &InnerKey{Key: strconv.Itoa(idx)}})
+		require.NoError(t, err)
+	}
+
+	// Nothing can move, so every limited change which moves a key is rejected and leaves the ring unchanged.
+	ring.MaxMovementFraction = 0.001
+	state := ring.State()
+	c := ring.WatchAll()
+
+	require.ErrorIs(t, ring.CreateNodes([]Node{{Identifier: "C", VFactor: 4}}), ErrMovementExceeded)
+	require.ErrorIs(t, ring.UpdateNode(Node{Identifier: "A", VFactor: 4}), ErrMovementExceeded)
+	_, err = ring.ResizeNodes(map[string]int{"A": 4, "B": 2})
+	require.ErrorIs(t, err, ErrMovementExceeded)
+	require.ErrorIs(t, ring.DrainNode("A"), ErrMovementExceeded)
+	require.ErrorIs(t, ring.TryDeleteNode("A"), ErrMovementExceeded)
+	require.ErrorIs(t, ring.DeleteNodeErr("A"), ErrMovementExceeded)
+
+	require.Equal(t, state, ring.State())
+	require.Equal(t, 1, ring.vFactorByNode["A"])
+	require.Empty(t, c)
+	ring.UnwatchAll(c)
+
+	// DeleteNode is never limited, so a failed node can always be removed.
+	ring.DeleteNode("A")
+	require.Equal(t, []string{"B"}, ring.ListNodes())
+}
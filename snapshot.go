@@ -0,0 +1,540 @@
+package ring
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// snapshotMagic prefixes every binary snapshot, followed by the snapshot format version.
+const (
+	snapshotMagic   = "RING"
+	snapshotVersion = 4
+
+	// snapshotVersionWithoutZones, snapshotVersionWithoutHashKeys and snapshotVersionWithoutPins are the snapshot
+	// format versions written before nodes had zones, before keys retained their hash keys, and before pinned keys
+	// were persisted, which can still be read.
+	snapshotVersionWithoutZones    = 1
+	snapshotVersionWithoutHashKeys = 2
+	snapshotVersionWithoutPins     = 3
+
+	// maxSnapshotField bounds the length of any single string or payload read from a snapshot,
+	// so that a corrupt length cannot trigger an arbitrarily large allocation.
+	maxSnapshotField = 1 << 30
+)
+
+// PayloadCodec is an interface whose implementation encodes and decodes key payloads when the ring
+// is written to or read from a snapshot.
+type PayloadCodec[T any] interface {
+	EncodePayload(payload T) ([]byte, error)
+	DecodePayload(data []byte) (T, error)
+}
+
+// JSONCodec is the default PayloadCodec, which encodes payloads as JSON.
+type JSONCodec[T any] struct{}
+
+// EncodePayload encodes the payload as JSON.
+func (JSONCodec[T]) EncodePayload(payload T) ([]byte, error) {
+	return json.Marshal(payload)
+}
+
+// DecodePayload decodes a payload from JSON.
+func (JSONCodec[T]) DecodePayload(data []byte) (T, error) {
+	var payload T
+	err := json.Unmarshal(data, &payload)
+	return payload, err
+}
+
+// snapshot is the complete state of a ring, from which every internal structure can be rebuilt.
+type snapshot[T any] struct {
+	VFactorByNode map[string]int
+	ZoneByNode    map[string]string
+	NodesBySlice  map[uint64]string
+	Keys          []snapshotKey[T]
+	PinnedKeys    map[string]string
+}
+
+type snapshotKey[T any] struct {
+	Key     string
+	Order   int
+	Hash    uint64
+	HashKey string
+	Payload T
+}
+
+// WriteTo writes the entire state of the ring, including payloads encoded with the PayloadCodec, to the writer
+// in a compact binary format. It implements io.WriterTo and returns the number of bytes written.
+func (ring *Ring[T]) WriteTo(w io.Writer) (int64, error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	counter := &countingWriter{w: w}
+	buffered := bufio.NewWriter(counter)
+	enc := &encoder{w: buffered}
+
+	enc.putBytes([]byte(snapshotMagic))
+	enc.putUint8(snapshotVersion)
+
+	// Write nodes in a stable order so that identical rings produce identical snapshots.
+	nodes := make([]string, 0, len(ring.vFactorByNode))
+	for node := range ring.vFactorByNode {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	enc.putUint32(uint32(len(nodes)))
+	for _, node := range nodes {
+		enc.putString(node)
+		enc.putUint64(uint64(ring.vFactorByNode[node]))
+		enc.putString(ring.zoneByNode[node])
+	}
+
+	enc.putUint32(uint32(len(ring.slices)))
+	for _, slice := range ring.slices {
+		enc.putUint64(slice)
+		enc.putString(ring.nodesBySlice[slice])
+	}
+
+	enc.putUint32(uint32(len(ring.hashesByKey)))
+	for _, hash := range ring.hashes {
+		for _, key := range ring.keysByHash[hash] {
+			payload, err := ring.PayloadCodec.EncodePayload(ring.contentByKey[key.Key])
+			if err != nil {
+				return counter.n, err
+			}
+
+			enc.putString(key.Key)
+			enc.putUint64(uint64(key.Order))
+			enc.putUint64(hash)
+			enc.putString(ring.hashKeyByKey[key.Key])
+			enc.putString(string(payload))
+		}
+	}
+
+	pinned := make([]string, 0, len(ring.pinnedKeys))
+	for key := range ring.pinnedKeys {
+		pinned = append(pinned, key)
+	}
+	sort.Strings(pinned)
+
+	enc.putUint32(uint32(len(pinned)))
+	for _, key := range pinned {
+		enc.putString(key)
+		enc.putString(ring.pinnedKeys[key])
+	}
+
+	if enc.err != nil {
+		return counter.n, enc.err
+	}
+
+	err := buffered.Flush()
+	return counter.n, err
+}
+
+// ReadFrom replaces the entire state of the ring with a snapshot previously produced by WriteTo, decoding payloads
+// with the PayloadCodec. It implements io.ReaderFrom and returns the number of bytes read. Watchers are not notified
+// of any keys loaded from the snapshot, and the ring is left unchanged if the snapshot is invalid. Expiries are not
+// part of a snapshot, so keys loaded from a snapshot never expire.
+func (ring *Ring[T]) ReadFrom(r io.Reader) (int64, error) {
+	defer ring.timeOp("ReadFrom")()
+
+	dec := &decoder{r: r}
+
+	magic := string(dec.bytes(len(snapshotMagic)))
+	version := dec.uint8()
+	if dec.err != nil {
+		return dec.n, dec.err
+	}
+	if magic != snapshotMagic || version < snapshotVersionWithoutZones || version > snapshotVersion {
+		return dec.n, ErrInvalidSnapshot
+	}
+
+	s := &snapshot[T]{
+		VFactorByNode: make(map[string]int),
+		ZoneByNode:    make(map[string]string),
+		NodesBySlice:  make(map[uint64]string),
+		PinnedKeys:    make(map[string]string),
+	}
+
+	for count := dec.uint32(); count > 0 && dec.err == nil; count-- {
+		node := dec.string()
+		s.VFactorByNode[node] = int(dec.uint64())
+		if version > snapshotVersionWithoutZones {
+			zone := dec.string()
+			if zone != "" {
+				s.ZoneByNode[node] = zone
+			}
+		}
+	}
+
+	for count := dec.uint32(); count > 0 && dec.err == nil; count-- {
+		slice := dec.uint64()
+		s.NodesBySlice[slice] = dec.string()
+	}
+
+	for count := dec.uint32(); count > 0 && dec.err == nil; count-- {
+		key := snapshotKey[T]{
+			Key:   dec.string(),
+			Order: int(dec.uint64()),
+			Hash:  dec.uint64(),
+		}
+		if version > snapshotVersionWithoutHashKeys {
+			key.HashKey = dec.string()
+		}
+		data := dec.string()
+		if dec.err != nil {
+			break
+		}
+
+		payload, err := ring.PayloadCodec.DecodePayload([]byte(data))
+		if err != nil {
+			return dec.n, err
+		}
+		key.Payload = payload
+
+		s.Keys = append(s.Keys, key)
+	}
+
+	if version > snapshotVersionWithoutPins {
+		for count := dec.uint32(); count > 0 && dec.err == nil; count-- {
+			key := dec.string()
+			s.PinnedKeys[key] = dec.string()
+		}
+	}
+
+	if dec.err != nil {
+		return dec.n, dec.err
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	return dec.n, ring.restore(s)
+}
+
+// ringJSON is the JSON encoding of a ring, as its state along with the payload of each key and the node of each
+// pinned key.
+type ringJSON[T any] struct {
+	State      *State            `json:"state"`
+	Payloads   map[string]T      `json:"payloads"`
+	PinnedKeys map[string]string `json:"pinnedKeys,omitempty"`
+}
+
+// MarshalJSON encodes the state of the ring as provided by State, along with the payload of every key.
+// Payloads are encoded with encoding/json rather than the PayloadCodec.
+func (ring *Ring[T]) MarshalJSON() ([]byte, error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	return json.Marshal(ringJSON[T]{
+		State:      ring.state(),
+		Payloads:   ring.contentByKey,
+		PinnedKeys: ring.pinnedKeys,
+	})
+}
+
+// UnmarshalJSON replaces the entire state of the ring with one previously encoded by MarshalJSON, loading the
+// state as LoadState would, but with the payload of every key. The ring must already have been created with New.
+// ErrInconsistentState is returned, leaving the ring unchanged, if the encoded state is inconsistent.
+func (ring *Ring[T]) UnmarshalJSON(data []byte) error {
+	defer ring.timeOp("UnmarshalJSON")()
+
+	var decoded ringJSON[T]
+	err := json.Unmarshal(data, &decoded)
+	if err != nil {
+		return err
+	}
+	if decoded.State == nil {
+		return ErrInconsistentState
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	return ring.loadState(decoded.State, decoded.Payloads, decoded.PinnedKeys)
+}
+
+// Snapshot encodes the entire state of the ring, including the payload and order of every key, to the writer
+// with encoding/gob. Payloads are encoded by gob rather than the PayloadCodec, so a payload type containing
+// interface values must have their concrete types registered with gob.Register by the caller.
+func (ring *Ring[T]) Snapshot(w io.Writer) error {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	return gob.NewEncoder(w).Encode(ring.snapshot())
+}
+
+// Restore replaces the entire state of the ring with a snapshot previously encoded by Snapshot. Watchers are not
+// notified of any keys loaded from the snapshot, and the ring is left unchanged if the snapshot is invalid.
+// Expiries are not part of a snapshot, so keys loaded from a snapshot never expire.
+func (ring *Ring[T]) Restore(r io.Reader) error {
+	defer ring.timeOp("Restore")()
+
+	s := &snapshot[T]{}
+	err := gob.NewDecoder(r).Decode(s)
+	if err != nil {
+		return err
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	return ring.restore(s)
+}
+
+// snapshot copies the entire state of the ring, listing keys in ascending hash order and then by order.
+func (ring *Ring[T]) snapshot() *snapshot[T] {
+	s := &snapshot[T]{
+		VFactorByNode: make(map[string]int, len(ring.vFactorByNode)),
+		ZoneByNode:    make(map[string]string, len(ring.zoneByNode)),
+		NodesBySlice:  make(map[uint64]string, len(ring.nodesBySlice)),
+		Keys:          make([]snapshotKey[T], 0, len(ring.hashesByKey)),
+		PinnedKeys:    make(map[string]string, len(ring.pinnedKeys)),
+	}
+
+	for node, vFactor := range ring.vFactorByNode {
+		s.VFactorByNode[node] = vFactor
+	}
+	for node, zone := range ring.zoneByNode {
+		s.ZoneByNode[node] = zone
+	}
+	for slice, node := range ring.nodesBySlice {
+		s.NodesBySlice[slice] = node
+	}
+	for key, node := range ring.pinnedKeys {
+		s.PinnedKeys[key] = node
+	}
+
+	for _, hash := range ring.hashes {
+		for _, key := range ring.keysByHash[hash] {
+			s.Keys = append(s.Keys, snapshotKey[T]{
+				Key:     key.Key,
+				Order:   key.Order,
+				Hash:    hash,
+				HashKey: ring.hashKeyByKey[key.Key],
+				Payload: ring.contentByKey[key.Key],
+			})
+		}
+	}
+
+	return s
+}
+
+// restore replaces the entire state of the ring with the snapshot, without notifying watchers.
+// The snapshot is validated first, so the ring is left unchanged if it is inconsistent.
+func (ring *Ring[T]) restore(s *snapshot[T]) error {
+
+	// Every node must have a valid VFactor, every zone and slice must belong to a known node, every key must
+	// be unique, and every pinned key must be a known key pinned to a known node.
+	for _, vFactor := range s.VFactorByNode {
+		if vFactor < 1 {
+			return ErrInconsistentState
+		}
+	}
+	for node := range s.ZoneByNode {
+		_, ok := s.VFactorByNode[node]
+		if !ok {
+			return ErrInconsistentState
+		}
+	}
+	for _, node := range s.NodesBySlice {
+		_, ok := s.VFactorByNode[node]
+		if !ok {
+			return ErrInconsistentState
+		}
+	}
+	seen := make(map[string]struct{}, len(s.Keys))
+	for _, key := range s.Keys {
+		_, ok := seen[key.Key]
+		if ok {
+			return ErrInconsistentState
+		}
+		seen[key.Key] = struct{}{}
+	}
+	for key, node := range s.PinnedKeys {
+		_, ok := seen[key]
+		if !ok {
+			return ErrInconsistentState
+		}
+		_, ok = s.VFactorByNode[node]
+		if !ok {
+			return ErrInconsistentState
+		}
+	}
+
+	ring.slices = nil
+	ring.hashes = nil
+	ring.empty = make(map[uint64]uint64)
+	ring.nodesBySlice = make(map[uint64]string, len(s.NodesBySlice))
+	ring.vFactorByNode = make(map[string]int, len(s.VFactorByNode))
+	ring.zoneByNode = make(map[string]string, len(s.ZoneByNode))
+	ring.slicesByHash = make(map[uint64]uint64)
+	ring.keysByHash = make(map[uint64][]*InnerKey)
+	ring.rankByKey = make(map[string]keyRank)
+	ring.contentByKey = make(map[string]T, len(s.Keys))
+	ring.hashesByKey = make(map[string]uint64, len(s.Keys))
+	ring.keysByIndex = make(map[string][]string)
+	ring.touchedByKey = make(map[string]time.Time, len(s.Keys))
+	ring.hashKeyByKey = make(map[string]string)
+	ring.expiryByKey = make(map[string]time.Time)
+	ring.expiries = nil
+	ring.pinnedKeys = make(map[string]string, len(s.PinnedKeys))
+
+	for node, vFactor := range s.VFactorByNode {
+		ring.vFactorByNode[node] = vFactor
+	}
+	for node, zone := range s.ZoneByNode {
+		ring.zoneByNode[node] = zone
+	}
+	for key, node := range s.PinnedKeys {
+		ring.pinnedKeys[key] = node
+	}
+
+	for slice, node := range s.NodesBySlice {
+		ring.slices = append(ring.slices, slice)
+		ring.nodesBySlice[slice] = node
+	}
+	sort.Slice(ring.slices, func(i, j int) bool { return ring.slices[i] < ring.slices[j] })
+
+	// Keys are inserted ahead of any keys with the same order, so insert them in reverse to preserve their order.
+	now := ring.Clock.Now()
+	for idx := len(s.Keys) - 1; idx >= 0; idx-- {
+		key := s.Keys[idx]
+		_, ok := ring.keysByHash[key.Hash]
+		if !ok {
+			ring.hashes = append(ring.hashes, key.Hash)
+		}
+
+		ring.insertKey(key.Hash, &InnerKey{Key: key.Key, Order: key.Order})
+		ring.hashesByKey[key.Key] = key.Hash
+		ring.contentByKey[key.Key] = key.Payload
+		ring.touchedByKey[key.Key] = now
+		if key.HashKey != "" && key.HashKey != key.Key {
+			ring.hashKeyByKey[key.Key] = key.HashKey
+		}
+		ring.index(key.Key, key.Payload)
+	}
+	sort.Slice(ring.hashes, func(i, j int) bool { return ring.hashes[i] < ring.hashes[j] })
+
+	// Assign every hash to its slice, or to the empty container if there are no slices.
+	for _, hash := range ring.hashes {
+		if len(ring.slices) == 0 {
+			ring.empty[hash] = hash
+			continue
+		}
+
+		ring.slicesByHash[hash] = ring.slices[findPrevIndex(ring.slices, findIndex(ring.slices, hash))]
+	}
+	ring.viewStale = true
+	ring.refreshView()
+	ring.publishKeys()
+
+	return nil
+}
+
+// countingWriter counts the bytes successfully written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (counter *countingWriter) Write(p []byte) (int, error) {
+	n, err := counter.w.Write(p)
+	counter.n += int64(n)
+	return n, err
+}
+
+// encoder writes big endian values, retaining the first error encountered so that it only needs to be checked once.
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+func (enc *encoder) putBytes(p []byte) {
+	if enc.err != nil {
+		return
+	}
+	_, enc.err = enc.w.Write(p)
+}
+
+func (enc *encoder) putUint8(v uint8) {
+	enc.putBytes([]byte{v})
+}
+
+func (enc *encoder) putUint32(v uint32) {
+	enc.putBytes(binary.BigEndian.AppendUint32(nil, v))
+}
+
+func (enc *encoder) putUint64(v uint64) {
+	enc.putBytes(binary.BigEndian.AppendUint64(nil, v))
+}
+
+func (enc *encoder) putString(v string) {
+	enc.putUint32(uint32(len(v)))
+	enc.putBytes([]byte(v))
+}
+
+// decoder reads big endian values, retaining the first error encountered and counting the bytes read.
+type decoder struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func (dec *decoder) bytes(length int) []byte {
+	if dec.err != nil {
+		return nil
+	}
+
+	p := make([]byte, length)
+	n, err := io.ReadFull(dec.r, p)
+	dec.n += int64(n)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	dec.err = err
+
+	return p
+}
+
+func (dec *decoder) uint8() uint8 {
+	p := dec.bytes(1)
+	if dec.err != nil {
+		return 0
+	}
+	return p[0]
+}
+
+func (dec *decoder) uint32() uint32 {
+	p := dec.bytes(4)
+	if dec.err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(p)
+}
+
+func (dec *decoder) uint64() uint64 {
+	p := dec.bytes(8)
+	if dec.err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(p)
+}
+
+func (dec *decoder) string() string {
+	length := dec.uint32()
+	if dec.err != nil {
+		return ""
+	}
+
+	if length > maxSnapshotField {
+		dec.err = ErrInvalidSnapshot
+		return ""
+	}
+
+	return string(dec.bytes(int(length)))
+}
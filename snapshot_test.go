@@ -0,0 +1,350 @@
+package ring
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type snapshotPayload struct {
+	Name  string
+	Count int
+}
+
+func populatedSnapshotRing(t *testing.T) *Ring[snapshotPayload] {
+	ring, err := New(func(r *Ring[snapshotPayload]) {
+		r.BaseVFactor = 2
+	})
+	require.NoError(t, err)
+
+	for identifier, zone := range map[string]string{"A": "east", "B": "east", "C": ""} {
+		err = ring.CreateNode(Node{
+			Identifier: identifier,
+			VFactor:    2,
+			Zone:       zone,
+		})
+		require.NoError(t, err)
+	}
+
+	for idx := 0; idx < 50; idx++ {
+		err = ring.Emplace(&Key[snapshotPayload]{
+			InnerKey: &InnerKey{Key: strconv.Itoa(idx), Order: idx % 3},
+			Value:    snapshotPayload{Name: strconv.Itoa(idx), Count: idx},
+		}, strconv.Itoa(idx%20))
+		require.NoError(t, err)
+	}
+
+	err = ring.Pin("7", "C")
+	require.NoError(t, err)
+
+	return ring
+}
+
+func requireSameRing[T any](t *testing.T, expected, actual *Ring[T]) {
+	require.Equal(t, expected.slices, actual.slices)
+	require.Equal(t, expected.hashes, actual.hashes)
+	require.Equal(t, expected.empty, actual.empty)
+	require.Equal(t, expected.nodesBySlice, actual.nodesBySlice)
+	require.Equal(t, expected.vFactorByNode, actual.vFactorByNode)
+	require.Equal(t, expected.zoneByNode, actual.zoneByNode)
+	require.Equal(t, expected.slicesByHash, actual.slicesByHash)
+	require.Equal(t, expected.keysByHash, actual.keysByHash)
+	require.Equal(t, expected.contentByKey, actual.contentByKey)
+	require.Equal(t, expected.hashesByKey, actual.hashesByKey)
+	require.Equal(t, expected.hashKeyByKey, actual.hashKeyByKey)
+	require.Equal(t, expected.pinnedKeys, actual.pinnedKeys)
+}
+
+func TestWriteToReadFromRoundTrip(t *testing.T) {
+	source := populatedSnapshotRing(t)
+
+	var buf bytes.Buffer
+	written, err := source.WriteTo(&buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(buf.Len()), written)
+
+	ring, err := New[snapshotPayload]()
+	require.NoError(t, err)
+
+	read, err := ring.ReadFrom(&buf)
+	require.NoError(t, err)
+	require.Equal(t, written, read)
+
+	requireSameRing(t, source, ring)
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	source := populatedSnapshotRing(t)
+
+	data, err := json.Marshal(source)
+	require.NoError(t, err)
+
+	ring, err := New(func(r *Ring[snapshotPayload]) {
+		r.BaseVFactor = 2
+	})
+	require.NoError(t, err)
+
+	err = json.Unmarshal(data, ring)
+	require.NoError(t, err)
+
+	require.Equal(t, source.State(), ring.State())
+	require.Equal(t, source.vFactorByNode, ring.vFactorByNode)
+	require.Equal(t, source.contentByKey, ring.contentByKey)
+	require.Equal(t, map[string]string{"7": "C"}, ring.pinnedKeys)
+
+	payload, err := ring.GetPayload("7")
+	require.NoError(t, err)
+	require.Equal(t, snapshotPayload{Name: "7", Count: 7}, payload)
+}
+
+func TestUnmarshalJSONInconsistent(t *testing.T) {
+	ring := populatedSnapshotRing(t)
+	state := ring.State()
+
+	for _, data := range []string{
+		`{}`,
+		`{"state":{"nodesBySlice":{},"slicesByHash":{},"hashesByKey":{}},"payloads":{"1":{"Name":"1"}}}`,
+		`{"state":{"nodesBySlice":{"10":"A"},"slicesByHash":{},"hashesByKey":{}},"payloads":{},"pinnedKeys":{"1":"A"}}`,
+	} {
+		err := json.Unmarshal([]byte(data), ring)
+		require.ErrorIs(t, err, ErrInconsistentState, data)
+	}
+
+	err := json.Unmarshal([]byte(`{"state":`), ring)
+	require.Error(t, err)
+
+	require.Equal(t, state, ring.State())
+}
+
+func TestSnapshotRestoreRoundTrip(t *testing.T) {
+	source := populatedSnapshotRing(t)
+
+	var buf bytes.Buffer
+	err := source.Snapshot(&buf)
+	require.NoError(t, err)
+
+	ring, err := New[snapshotPayload]()
+	require.NoError(t, err)
+
+	err = ring.Restore(&buf)
+	require.NoError(t, err)
+
+	require.Equal(t, source.State(), ring.State())
+	requireSameRing(t, source, ring)
+}
+
+func TestRestoreInvalidSnapshot(t *testing.T) {
+	ring := populatedSnapshotRing(t)
+	state := ring.State()
+
+	err := ring.Restore(bytes.NewBufferString("not a snapshot"))
+	require.Error(t, err)
+
+	require.Equal(t, state, ring.State())
+}
+
+func TestRestoreInconsistentSnapshot(t *testing.T) {
+	ring := populatedSnapshotRing(t)
+	state := ring.State()
+
+	for _, s := range []*snapshot[snapshotPayload]{
+		{
+			VFactorByNode: map[string]int{"A": 0},
+			NodesBySlice:  map[uint64]string{10: "A"},
+		},
+		{
+			VFactorByNode: map[string]int{"A": 1},
+			ZoneByNode:    map[string]string{"B": "east"},
+			NodesBySlice:  map[uint64]string{10: "A"},
+		},
+		{
+			VFactorByNode: map[string]int{"A": 1},
+			NodesBySlice:  map[uint64]string{10: "A"},
+			Keys:          []snapshotKey[snapshotPayload]{{Key: "1", Hash: 20}},
+			PinnedKeys:    map[string]string{"1": "B"},
+		},
+	} {
+		var buf bytes.Buffer
+		err := gob.NewEncoder(&buf).Encode(s)
+		require.NoError(t, err)
+
+		err = ring.Restore(&buf)
+		require.ErrorIs(t, err, ErrInconsistentState)
+	}
+
+	require.Equal(t, state, ring.State())
+}
+
+func TestReadFromSnapshotWithoutZones(t *testing.T) {
+	var buf bytes.Buffer
+	enc := &encoder{w: &buf}
+	enc.putBytes([]byte(snapshotMagic))
+	enc.putUint8(snapshotVersionWithoutZones)
+	enc.putUint32(1)
+	enc.putString("A")
+	enc.putUint64(1)
+	enc.putUint32(1)
+	enc.putUint64(10)
+	enc.putString("A")
+	enc.putUint32(0)
+	require.NoError(t, enc.err)
+
+	ring, err := New[int]()
+	require.NoError(t, err)
+
+	_, err = ring.ReadFrom(&buf)
+	require.NoError(t, err)
+
+	node, err := ring.GetNode("A")
+	require.NoError(t, err)
+	require.Equal(t, Node{Identifier: "A", VFactor: 1}, node)
+	require.Equal(t, []uint64{10}, ring.slices)
+}
+
+func TestReadFromSnapshotWithoutHashKeys(t *testing.T) {
+	var buf bytes.Buffer
+	enc := &encoder{w: &buf}
+	enc.putBytes([]byte(snapshotMagic))
+	enc.putUint8(snapshotVersionWithoutHashKeys)
+	enc.putUint32(1)
+	enc.putString("A")
+	enc.putUint64(1)
+	enc.putString("east")
+	enc.putUint32(1)
+	enc.putUint64(10)
+	enc.putString("A")
+	enc.putUint32(1)
+	enc.putString("1")
+	enc.putUint64(0)
+	enc.putUint64(20)
+	enc.putString("7")
+	require.NoError(t, enc.err)
+
+	ring, err := New[int]()
+	require.NoError(t, err)
+
+	_, err = ring.ReadFrom(&buf)
+	require.NoError(t, err)
+
+	node, err := ring.GetNode("A")
+	require.NoError(t, err)
+	require.Equal(t, Node{Identifier: "A", VFactor: 1, Zone: "east"}, node)
+	require.Equal(t, uint64(20), ring.hashesByKey["1"])
+	require.Equal(t, 7, ring.contentByKey["1"])
+	require.Empty(t, ring.hashKeyByKey)
+}
+
+func TestReadFromSnapshotWithoutPins(t *testing.T) {
+	var buf bytes.Buffer
+	enc := &encoder{w: &buf}
+	enc.putBytes([]byte(snapshotMagic))
+	enc.putUint8(snapshotVersionWithoutPins)
+	enc.putUint32(1)
+	enc.putString("A")
+	enc.putUint64(1)
+	enc.putString("")
+	enc.putUint32(1)
+	enc.putUint64(10)
+	enc.putString("A")
+	enc.putUint32(1)
+	enc.putString("1")
+	enc.putUint64(0)
+	enc.putUint64(20)
+	enc.putString("")
+	enc.putString("7")
+	require.NoError(t, enc.err)
+
+	ring, err := New[int]()
+	require.NoError(t, err)
+
+	_, err = ring.ReadFrom(&buf)
+	require.NoError(t, err)
+
+	require.Equal(t, uint64(20), ring.hashesByKey["1"])
+	require.Empty(t, ring.pinnedKeys)
+}
+
+func TestWriteToReadFromEmptyContainer(t *testing.T) {
+	source, err := New[int]()
+	require.NoError(t, err)
+
+	err = source.Emplace(&Key[int]{InnerKey: &InnerKey{Key: "1"}, Value: 1})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = source.WriteTo(&buf)
+	require.NoError(t, err)
+
+	ring, err := New[int]()
+	require.NoError(t, err)
+
+	_, err = ring.ReadFrom(&buf)
+	require.NoError(t, err)
+
+	requireSameRing(t, source, ring)
+}
+
+type upperCodec struct{}
+
+func (upperCodec) EncodePayload(payload string) ([]byte, error) {
+	return []byte("<" + payload + ">"), nil
+}
+
+func (upperCodec) DecodePayload(data []byte) (string, error) {
+	return string(data[1 : len(data)-1]), nil
+}
+
+func TestWriteToReadFromPayloadCodec(t *testing.T) {
+	source, err := New(func(r *Ring[string]) {
+		r.PayloadCodec = upperCodec{}
+	})
+	require.NoError(t, err)
+
+	err = source.Emplace(&Key[string]{InnerKey: &InnerKey{Key: "1"}, Value: "payload"})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = source.WriteTo(&buf)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "<payload>")
+
+	ring, err := New(func(r *Ring[string]) {
+		r.PayloadCodec = upperCodec{}
+	})
+	require.NoError(t, err)
+
+	_, err = ring.ReadFrom(&buf)
+	require.NoError(t, err)
+	require.Equal(t, "payload", ring.contentByKey["1"])
+}
+
+func TestReadFromInvalidSnapshot(t *testing.T) {
+	source := populatedSnapshotRing(t)
+
+	var buf bytes.Buffer
+	_, err := source.WriteTo(&buf)
+	require.NoError(t, err)
+
+	ring, err := New[snapshotPayload]()
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{
+		Identifier: "D",
+		VFactor:    1,
+	})
+	require.NoError(t, err)
+
+	_, err = ring.ReadFrom(bytes.NewReader([]byte("NOPE!")))
+	require.Equal(t, ErrInvalidSnapshot, err)
+
+	// A truncated snapshot leaves the ring unchanged.
+	_, err = ring.ReadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()/2]))
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+
+	require.Equal(t, map[string]int{"D": 1}, ring.vFactorByNode)
+}
@@ -0,0 +1,121 @@
+package ring
+
+import (
+	"container/heap"
+	"time"
+)
+
+// minStaleExpiries is the number of stale expiries tolerated before the expiry heap is compacted.
+const minStaleExpiries = 64
+
+// expiry is the absolute time at which a key emplaced with a TTL expires.
+type expiry struct {
+	key string
+	at  time.Time
+}
+
+// expiryHeap is a min heap of expiries ordered by time. Expiries are deleted lazily, so an entry is stale
+// unless it matches the current expiry of its key.
+type expiryHeap []expiry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+func (h expiryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *expiryHeap) Push(x any) {
+	*h = append(*h, x.(expiry))
+}
+
+func (h *expiryHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// EmplaceTTL attempts to add a key to the ring like Emplace, additionally recording that the key expires
+// once the ttl has elapsed on the ring's Clock. Expired keys are removed by ReapExpired.
+func (ring *Ring[T]) EmplaceTTL(key *Key[T], ttl time.Duration, hk ...string) error {
+	defer ring.timeOp("EmplaceTTL")()
+
+	if key == nil {
+		return ErrNilKey
+	}
+
+	if ttl <= 0 {
+		return ErrInvalidTTL
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	_, ok := ring.hashesByKey[key.InnerKey.Key]
+	if ok {
+		return ErrKeyAlreadyExists
+	}
+
+	hashKey := hashKeyOf(key, hk)
+	hash := ring.HashKey(hashKey)
+	err := ring.checkKeyHash(hash)
+	if err != nil {
+		return err
+	}
+	ring.emplace(key, hashKey, hash)
+
+	at := ring.Clock.Now().Add(ttl)
+	ring.expiryByKey[key.InnerKey.Key] = at
+	heap.Push(&ring.expiries, expiry{key: key.InnerKey.Key, at: at})
+
+	return nil
+}
+
+// ReapExpired removes every key whose TTL has elapsed, notifying watchers of each removal as Remove would,
+// and returns the removed keys in the order they expired. Keys emplaced without a TTL are never reaped,
+// and are never examined. It is intended to be called periodically.
+func (ring *Ring[T]) ReapExpired() []string {
+	defer ring.timeOp("ReapExpired")()
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	now := ring.Clock.Now()
+
+	var reaped []string
+	for len(ring.expiries) > 0 && !ring.expiries[0].at.After(now) {
+		next := heap.Pop(&ring.expiries).(expiry)
+
+		// Skip the expiry if the key has since been removed or re-emplaced.
+		at, ok := ring.expiryByKey[next.key]
+		if !ok || !at.Equal(next.at) {
+			continue
+		}
+
+		ring.remove(next.key)
+		reaped = append(reaped, next.key)
+	}
+
+	return reaped
+}
+
+// forgetExpiry deletes the expiry of a removed key, compacting the expiry heap once most of it is stale.
+func (ring *Ring[T]) forgetExpiry(key string) {
+	_, ok := ring.expiryByKey[key]
+	if !ok {
+		return
+	}
+	delete(ring.expiryByKey, key)
+
+	if len(ring.expiries) < minStaleExpiries || len(ring.expiries) < 2*len(ring.expiryByKey) {
+		return
+	}
+
+	live := ring.expiries[:0]
+	for _, e := range ring.expiries {
+		at, ok := ring.expiryByKey[e.key]
+		if ok && at.Equal(e.at) {
+			live = append(live, e)
+		}
+	}
+	ring.expiries = live
+	heap.Init(&ring.expiries)
+}
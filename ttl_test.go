@@ -0,0 +1,118 @@
+package ring
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmplaceTTL(t *testing.T) {
+	clock := newFakeClock()
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.Clock = clock
+	})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{
+		Identifier: "A",
+		VFactor:    1,
+	})
+	require.NoError(t, err)
+
+	for _, ttl := range []time.Duration{3 * time.Second, time.Second, 2 * time.Second} {
+		err = ring.EmplaceTTL(&Key[RingPayloadType]{
+			InnerKey: &InnerKey{Key: ttl.String()},
+		}, ttl)
+		require.NoError(t, err)
+	}
+
+	err = ring.Emplace(&Key[RingPayloadType]{
+		InnerKey: &InnerKey{Key: "forever"},
+	})
+	require.NoError(t, err)
+
+	err = ring.EmplaceTTL(&Key[RingPayloadType]{
+		InnerKey: &InnerKey{Key: "forever"},
+	}, time.Second)
+	require.ErrorIs(t, err, ErrKeyAlreadyExists)
+
+	err = ring.EmplaceTTL(&Key[RingPayloadType]{
+		InnerKey: &InnerKey{Key: "never"},
+	}, 0)
+	require.ErrorIs(t, err, ErrInvalidTTL)
+
+	require.Empty(t, ring.ReapExpired())
+
+	clock.Advance(2 * time.Second)
+	require.Equal(t, []string{"1s", "2s"}, ring.ReapExpired())
+
+	// A key removed and emplaced again without a TTL no longer expires.
+	ring.Remove("3s")
+	err = ring.Emplace(&Key[RingPayloadType]{
+		InnerKey: &InnerKey{Key: "3s"},
+	})
+	require.NoError(t, err)
+
+	clock.Advance(time.Hour)
+	require.Empty(t, ring.ReapExpired())
+
+	_, ok := ring.hashesByKey["3s"]
+	require.True(t, ok)
+	_, ok = ring.hashesByKey["forever"]
+	require.True(t, ok)
+}
+
+func TestReapExpiredNotifiesRemoval(t *testing.T) {
+	clock := newFakeClock()
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.Clock = clock
+	})
+	require.NoError(t, err)
+
+	err = ring.CreateNode(Node{
+		Identifier: "A",
+		VFactor:    1,
+	})
+	require.NoError(t, err)
+
+	err = ring.EmplaceTTL(&Key[RingPayloadType]{
+		InnerKey: &InnerKey{Key: "1"},
+	}, time.Second)
+	require.NoError(t, err)
+
+	c := ring.RegisterWatcher(Op[RingPayloadType]{
+		Node: "A",
+	})
+
+	clock.Advance(time.Second)
+	go ring.ReapExpired()
+
+	require.Equal(t, Op[RingPayloadType]{
+		Key:     "1",
+		Node:    "A",
+		Removed: true,
+	}, <-c)
+}
+
+func TestExpiryCompaction(t *testing.T) {
+	ring, err := New(func(r *Ring[RingPayloadType]) {
+		r.Clock = newFakeClock()
+	})
+	require.NoError(t, err)
+
+	for idx := 0; idx < 4*minStaleExpiries; idx++ {
+		err = ring.EmplaceTTL(&Key[RingPayloadType]{
+			InnerKey: &InnerKey{Key: strconv.Itoa(idx)},
+		}, time.Hour)
+		require.NoError(t, err)
+	}
+
+	for idx := 0; idx < 3*minStaleExpiries; idx++ {
+		ring.Remove(strconv.Itoa(idx))
+	}
+
+	require.Len(t, ring.expiryByKey, minStaleExpiries)
+	require.LessOrEqual(t, len(ring.expiries), 2*len(ring.expiryByKey))
+}
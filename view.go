@@ -0,0 +1,161 @@
+package ring
+
+import (
+	"sort"
+	"sync"
+)
+
+// topologyView is an immutable copy of the positions of the ring's slices and the nodes owning them, along with how
+// hash keys are hashed, which is read without locking the ring. It is replaced as a whole once a topology change
+// completes, and is never modified, so it may be shared freely.
+type topologyView struct {
+	layout  []SlicePosition
+	hashKey func(string) uint64
+}
+
+// keyView is the hash of a key along with the node it is pinned to, if any, which GetNodeForKey reads without
+// locking the ring. It is replaced whenever the key is emplaced, pinned or unpinned.
+type keyView struct {
+	hash   uint64
+	node   string
+	pinned bool
+}
+
+// viewChange is the latest change to a slice which the topology view has yet to reflect.
+type viewChange struct {
+	node    string
+	removed bool
+}
+
+// recordViewChange notes that the slice was inserted for the node, or removed, so that the next topology view
+// only needs to apply the changed slices to the previous view.
+func (ring *Ring[T]) recordViewChange(slice uint64, node string, removed bool) {
+	if ring.noView {
+		return
+	}
+
+	if ring.viewChanges == nil {
+		ring.viewChanges = make(map[uint64]viewChange)
+	}
+	ring.viewChanges[slice] = viewChange{node: node, removed: removed}
+}
+
+// publishKey publishes the current hash and pin of the key for GetNodeForKey, or unpublishes the key if it has
+// been removed.
+func (ring *Ring[T]) publishKey(key string) {
+	if ring.noView {
+		return
+	}
+
+	hash, ok := ring.hashesByKey[key]
+	if !ok {
+		ring.keyViews.Load().Delete(key)
+		return
+	}
+
+	node, pinned := ring.pinnedKeys[key]
+	ring.keyViews.Load().Store(key, keyView{hash: hash, node: node, pinned: pinned})
+}
+
+// publishKeys replaces every published key at once, so that readers observe either the previous keys or the
+// current keys, and never a mixture of them.
+func (ring *Ring[T]) publishKeys() {
+	if ring.noView {
+		return
+	}
+
+	keyViews := &sync.Map{}
+	for key, hash := range ring.hashesByKey {
+		node, pinned := ring.pinnedKeys[key]
+		keyViews.Store(key, keyView{hash: hash, node: node, pinned: pinned})
+	}
+	ring.keyViews.Store(keyViews)
+}
+
+// refreshView publishes a topology view reflecting the current topology, invoking OnRingNonEmpty or OnRingEmpty
+// if the ring gained its first slices or lost its last. It must be called while the ring is locked for writing,
+// once a topology change is complete, so that readers never observe a partial change.
+func (ring *Ring[T]) refreshView() {
+	if ring.noView {
+		return
+	}
+
+	prev := ring.view.Load()
+	if prev != nil && !ring.viewStale && len(ring.viewChanges) == 0 {
+		return
+	}
+
+	hash, salt := ring.Hash, ring.KeySalt
+	view := &topologyView{
+		hashKey: func(key string) uint64 {
+			return hash(salt + key)
+		},
+	}
+	if prev == nil || ring.viewStale {
+		view.layout = make([]SlicePosition, len(ring.slices))
+		for idx, slice := range ring.slices {
+			view.layout[idx] = SlicePosition{Hash: slice, Node: ring.nodesBySlice[slice]}
+		}
+	} else {
+		view.layout = applyViewChanges(prev.layout, ring.viewChanges)
+	}
+	ring.viewChanges = nil
+	ring.viewStale = false
+
+	ring.view.Store(view)
+	if prev == nil {
+		return
+	}
+
+	switch {
+	case len(prev.layout) == 0 && len(view.layout) > 0 && ring.OnRingNonEmpty != nil:
+		ring.OnRingNonEmpty()
+	case len(prev.layout) > 0 && len(view.layout) == 0 && ring.OnRingEmpty != nil:
+		ring.OnRingEmpty()
+	}
+}
+
+// applyViewChanges builds a new layout from the previous layout and the changed slices in a single merging pass,
+// without modifying the previous layout.
+func applyViewChanges(prev []SlicePosition, changes map[uint64]viewChange) []SlicePosition {
+	changed := make([]uint64, 0, len(changes))
+	for slice := range changes {
+		changed = append(changed, slice)
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i] < changed[j] })
+
+	layout := make([]SlicePosition, 0, len(prev)+len(changed))
+	for len(prev) > 0 || len(changed) > 0 {
+		// Unchanged slices are copied as they were.
+		if len(changed) == 0 || len(prev) > 0 && prev[0].Hash < changed[0] {
+			layout, prev = append(layout, prev[0]), prev[1:]
+			continue
+		}
+
+		// A changed slice replaces any previous position at the same hash.
+		slice := changed[0]
+		if len(prev) > 0 && prev[0].Hash == slice {
+			prev = prev[1:]
+		}
+		changed = changed[1:]
+
+		change := changes[slice]
+		if !change.removed {
+			layout = append(layout, SlicePosition{Hash: slice, Node: change.node})
+		}
+	}
+
+	return layout
+}
+
+// lookup resolves the node which the hash would be assigned to in the view.
+func (view *topologyView) lookup(hash uint64) (string, bool) {
+	if len(view.layout) == 0 {
+		return "", false
+	}
+
+	// The hash belongs to the last slice before it, wrapping around to the final slice.
+	idx := sort.Search(len(view.layout), func(i int) bool { return view.layout[i].Hash >= hash })
+
+	return view.layout[findPrevIndex(view.layout, idx)].Node, true
+}